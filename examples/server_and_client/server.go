@@ -16,7 +16,7 @@ import (
 	amqp "github.com/rabbitmq/amqp091-go"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 
-	"github.com/weeb-vip/go-tracing-lib/internal/logger"
+	"github.com/weeb-vip/go-tracing-lib/logger"
 	"github.com/weeb-vip/go-tracing-lib/providers"
 	"github.com/weeb-vip/go-tracing-lib/providers/datadog"
 	"github.com/weeb-vip/go-tracing-lib/tracing"
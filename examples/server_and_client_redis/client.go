@@ -9,7 +9,7 @@ import (
 	"syscall"
 	"time"
 
-	"github.com/weeb-vip/go-tracing-lib/internal/logger"
+	"github.com/weeb-vip/go-tracing-lib/logger"
 	"github.com/weeb-vip/go-tracing-lib/examples/server_and_client/publisher"
 	"github.com/weeb-vip/go-tracing-lib/examples/server_and_client/redis"
 	"github.com/weeb-vip/go-tracing-lib/providers"
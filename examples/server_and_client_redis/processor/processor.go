@@ -6,6 +6,7 @@ import (
 	"errors"
 	"github.com/rs/zerolog/log"
 	"github.com/weeb-vip/go-tracing-lib/examples/server_and_client/publisher"
+	"github.com/weeb-vip/go-tracing-lib/tracing/retry"
 	"time"
 
 	"github.com/cenkalti/backoff"
@@ -65,40 +66,29 @@ func NewProcessor[T any](publisher Publisher[T], cfg PublisherConfig) *Processor
 func (p *ProcessorImpl[T]) Process(ctx context.Context, request *publisher.Event[T], fn ProcessorFunc[T]) error {
 	logger := log.Ctx(ctx).With().Logger()
 
-	// wrap operation
-	operation := func() error {
-		return fn(ctx, request.Payload)
-	}
-
 	if request.Retries >= 10 {
 
 		return ErrMaxRetries
 	}
 
-	duration := p.exponentialBackOff.NextBackOff()
-
-	if duration == backoff.Stop {
-		p.exponentialBackOff.Reset()
+	// each event backs off from the initial interval, the processor's
+	// back-off is copied so concurrent events do not share its state
+	newBackOff := func() retry.BackOff {
+		b := *p.exponentialBackOff
+		return &b
 	}
-	err := operation()
+	err := retry.Do(ctx, "process event", func(ctx context.Context) error {
+		return fn(ctx, request.Payload)
+	}, retry.WithBackOff(newBackOff))
 	if err != nil {
 
 		logger.Error().Err(err).Msg("failed to process event")
 
+		// republish events that exhausted their attempts to retry them later
 		request.Retries++
 
-		err = p.publisher.Publish(ctx, request)
-		if err != nil {
-			return err
-		}
-
-		// pause for (duration)
-		// before consuming next event
-		time.Sleep(duration)
-
-		return nil
+		return p.publisher.Publish(ctx, request)
 	}
-	p.exponentialBackOff.Reset()
 
 	return nil
 }
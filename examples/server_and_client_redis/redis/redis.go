@@ -4,7 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"github.com/weeb-vip/go-tracing-lib/internal/logger"
+	"github.com/weeb-vip/go-tracing-lib/logger"
 	"github.com/go-redis/redis/v8"
 	"github.com/rs/zerolog/log"
 	"net"
@@ -12,7 +12,7 @@ import (
 	amqp "github.com/rabbitmq/amqp091-go"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 
-	"github.com/weeb-vip/go-tracing-lib/internal/logger"
+	"github.com/weeb-vip/go-tracing-lib/logger"
 	"github.com/weeb-vip/go-tracing-lib/examples/server_and_client/consumer"
 	"github.com/weeb-vip/go-tracing-lib/examples/server_and_client/processor"
 	"github.com/weeb-vip/go-tracing-lib/examples/server_and_client/publisher"
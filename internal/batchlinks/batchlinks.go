@@ -0,0 +1,52 @@
+package batchlinks
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// DefaultMax caps the links of a batch span when no limit is configured
+	DefaultMax = 128
+
+	DroppedKey = attribute.Key("messaging.batch.links_dropped")
+)
+
+// Links collects the links of a batch processing span to the producers of its messages
+type Links struct {
+	max     int
+	links   []trace.Link
+	count   int
+	dropped int
+}
+
+// New creates a collector keeping at most max links, a max below 1 keeps DefaultMax
+func New(max int) *Links {
+	if max < 1 {
+		max = DefaultMax
+	}
+	return &Links{max: max}
+}
+
+// Add counts a message and links to its producer span, ignoring invalid span contexts
+func (l *Links) Add(producer trace.SpanContext, attrs ...attribute.KeyValue) {
+	l.count++
+	if !producer.IsValid() {
+		return
+	}
+	if len(l.links) >= l.max {
+		l.dropped++
+		return
+	}
+	l.links = append(l.links, trace.Link{SpanContext: producer, Attributes: attrs})
+}
+
+// StartOptions returns the links and batch attributes to start the span with
+func (l *Links) StartOptions() []trace.SpanStartOption {
+	attrs := []attribute.KeyValue{semconv.MessagingBatchMessageCount(l.count)}
+	if l.dropped > 0 {
+		attrs = append(attrs, DroppedKey.Int(l.dropped))
+	}
+	return []trace.SpanStartOption{trace.WithLinks(l.links...), trace.WithAttributes(attrs...)}
+}
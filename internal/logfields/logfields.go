@@ -0,0 +1,58 @@
+package logfields
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/weeb-vip/go-tracing-lib/providers"
+)
+
+const (
+	GrafanaTraceIDKey = "traceID"
+	GrafanaSpanIDKey  = "spanID"
+	DatadogTraceIDKey = "dd.trace_id"
+	DatadogSpanIDKey  = "dd.span_id"
+)
+
+// Field is a single log field carrying a trace identifier
+type Field struct {
+	Key   string
+	Value string
+}
+
+// DefaultFormats are used when no format is configured
+var DefaultFormats = []providers.LogFormat{providers.LogFormatGrafana, providers.LogFormatDatadog}
+
+// FromContext returns the trace identifier fields for the span in ctx.
+// Nothing is returned when ctx does not carry a valid span context.
+func FromContext(ctx context.Context, formats []providers.LogFormat) []Field {
+	if ctx == nil {
+		return nil
+	}
+	return FromSpanContext(trace.SpanContextFromContext(ctx), formats)
+}
+
+// FromSpanContext returns the trace identifier fields for sc in each of the formats
+func FromSpanContext(sc trace.SpanContext, formats []providers.LogFormat) []Field {
+	if !sc.IsValid() {
+		return nil
+	}
+	if len(formats) == 0 {
+		formats = DefaultFormats
+	}
+
+	traceID := sc.TraceID().String()
+	spanID := sc.SpanID().String()
+
+	fields := make([]Field, 0, 2*len(formats))
+	for _, format := range formats {
+		switch format {
+		case providers.LogFormatGrafana:
+			fields = append(fields, Field{GrafanaTraceIDKey, traceID}, Field{GrafanaSpanIDKey, spanID})
+		case providers.LogFormatDatadog:
+			fields = append(fields, Field{DatadogTraceIDKey, traceID}, Field{DatadogSpanIDKey, spanID})
+		}
+	}
+	return fields
+}
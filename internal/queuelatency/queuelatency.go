@@ -0,0 +1,122 @@
+package queuelatency
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// Header carries the publish time of a message in Unix milliseconds
+	Header = "x-published-at"
+
+	// Key is the time in milliseconds a message spent queued
+	Key = attribute.Key("messaging.queue_latency")
+	// ClockSkewKey is set when the publish time is after the consume time,
+	// the latency is then recorded as 0
+	ClockSkewKey = attribute.Key("messaging.queue_latency.clock_skew")
+
+	metricName = "messaging.queue_latency"
+)
+
+// Recorder records queue latencies on consumer spans and in a histogram
+type Recorder struct {
+	histogram metric.Float64Histogram
+	now       func() time.Time
+}
+
+// New creates a recorder whose histogram belongs to the instrumentation scope
+func New(provider metric.MeterProvider, scope string) *Recorder {
+	histogram, err := provider.Meter(scope).Float64Histogram(metricName,
+		metric.WithUnit("ms"),
+		metric.WithDescription("Time messages spent queued between publish and consume"),
+	)
+	if err != nil {
+		// the returned instrument is still usable
+		otel.Handle(err)
+	}
+	return &Recorder{histogram: histogram, now: time.Now}
+}
+
+// Record adds the latency of a message published at publishedAt to the
+// histogram and returns it, a zero time records nothing. Negative latencies
+// caused by clock skew are recorded as 0 and flagged on span.
+func (r *Recorder) Record(ctx context.Context, span trace.Span, publishedAt time.Time, attrs ...attribute.KeyValue) (time.Duration, bool) {
+	if publishedAt.IsZero() {
+		return 0, false
+	}
+	latency := r.now().Sub(publishedAt)
+	if latency < 0 {
+		latency = 0
+		span.SetAttributes(ClockSkewKey.Bool(true))
+	}
+	r.histogram.Record(ctx, float64(latency)/float64(time.Millisecond), metric.WithAttributes(attrs...))
+	return latency, true
+}
+
+// RecordLongest records the latency of every message of a batch published at
+// times and sets the span attribute to the longest of them
+func (r *Recorder) RecordLongest(ctx context.Context, span trace.Span, times []time.Time, attrs ...attribute.KeyValue) {
+	var longest time.Duration
+	recorded := false
+	for _, publishedAt := range times {
+		if latency, ok := r.Record(ctx, span, publishedAt, attrs...); ok {
+			longest, recorded = max(longest, latency), true
+		}
+	}
+	if recorded {
+		SetAttribute(span, longest)
+	}
+}
+
+// SetAttribute sets the span attribute to latency
+func SetAttribute(span trace.Span, latency time.Duration) {
+	span.SetAttributes(Key.Int64(latency.Milliseconds()))
+}
+
+// Stamp returns the header value for a message published now
+func Stamp() int64 {
+	return time.Now().UnixMilli()
+}
+
+// Parse reads a header value in Unix milliseconds, as a number or a string
+func Parse(value any) time.Time {
+	var millis int64
+	switch v := value.(type) {
+	case int64:
+		millis = v
+	case int32:
+		millis = int64(v)
+	case int:
+		millis = int64(v)
+	case string:
+		parsed, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return time.Time{}
+		}
+		millis = parsed
+	case []byte:
+		return Parse(string(v))
+	default:
+		return time.Time{}
+	}
+	if millis <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(millis)
+}
+
+// FromStreamID returns the time encoded in a Redis stream entry ID, <milliseconds>-<sequence>
+func FromStreamID(id string) time.Time {
+	millis, _, ok := strings.Cut(id, "-")
+	if !ok {
+		return time.Time{}
+	}
+	return Parse(millis)
+}
@@ -0,0 +1,145 @@
+package rediscmd
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/weeb-vip/go-tracing-lib/logger"
+	"github.com/weeb-vip/go-tracing-lib/providers"
+)
+
+const (
+	InstrumentationName = "github.com/weeb-vip/go-tracing-lib/utils/redis"
+
+	KeyCountKey     = attribute.Key("db.redis.key_count")
+	PipelineSizeKey = attribute.Key("db.redis.num_cmd")
+)
+
+// Config is shared by the go-redis hooks of every major version
+type Config struct {
+	TracerProvider trace.TracerProvider
+	Attributes     []attribute.KeyValue
+	RawStatements  bool
+	Logging        bool
+	LogFormats     []providers.LogFormat
+}
+
+// Option configures a hook
+type Option func(*Config)
+
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *Config) {
+		c.TracerProvider = provider
+	}
+}
+
+func WithAttributes(attrs ...attribute.KeyValue) Option {
+	return func(c *Config) {
+		c.Attributes = append(c.Attributes, attrs...)
+	}
+}
+
+func WithRawStatements() Option {
+	return func(c *Config) {
+		c.RawStatements = true
+	}
+}
+
+func WithLogging(formats ...providers.LogFormat) Option {
+	return func(c *Config) {
+		c.Logging = true
+		if len(formats) > 0 {
+			c.LogFormats = formats
+		}
+	}
+}
+
+func NewConfig(opts []Option) *Config {
+	c := &Config{
+		TracerProvider: otel.GetTracerProvider(),
+		LogFormats:     logger.DefaultTraceFormats,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// StartCommand starts a client span for a single command
+func (c *Config) StartCommand(ctx context.Context, name string, args []any) (context.Context, trace.Span, string) {
+	statement := Statement(args, c.RawStatements)
+	attrs := append([]attribute.KeyValue{
+		semconv.DBSystemRedis,
+		semconv.DBOperation(name),
+		semconv.DBStatement(statement),
+		KeyCountKey.Int(KeyCount(args)),
+	}, c.Attributes...)
+
+	ctx, span := c.TracerProvider.Tracer(InstrumentationName).Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+	return ctx, span, statement
+}
+
+// StartPipeline starts a client span for a pipeline of commands
+func (c *Config) StartPipeline(ctx context.Context, names []string, args [][]any) (context.Context, trace.Span, string) {
+	statements := make([]string, len(args))
+	keys := 0
+	for i, cmdArgs := range args {
+		statements[i] = Statement(cmdArgs, c.RawStatements)
+		keys += KeyCount(cmdArgs)
+	}
+	statement := strings.Join(statements, "\n")
+
+	attrs := append([]attribute.KeyValue{
+		semconv.DBSystemRedis,
+		semconv.DBOperation(strings.Join(dedupe(names), " ")),
+		semconv.DBStatement(statement),
+		KeyCountKey.Int(keys),
+		PipelineSizeKey.Int(len(args)),
+	}, c.Attributes...)
+
+	ctx, span := c.TracerProvider.Tracer(InstrumentationName).Start(ctx, "redis.pipeline",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+	return ctx, span, statement
+}
+
+// Finish records err, which must exclude redis.Nil, on span, logs the command if enabled and ends span
+func (c *Config) Finish(ctx context.Context, span trace.Span, statement string, err error) {
+	defer span.End()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	if !c.Logging {
+		return
+	}
+
+	l := logger.FromContext(logger.ContextWithTraceFormats(ctx, c.LogFormats...))
+	if err != nil {
+		l.Error().Err(err).Str("db.statement", statement).Msg("redis command failed")
+		return
+	}
+	l.Debug().Str("db.statement", statement).Msg("redis command")
+}
+
+func dedupe(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	out := make([]string, 0, len(names))
+	for _, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			out = append(out, name)
+		}
+	}
+	return out
+}
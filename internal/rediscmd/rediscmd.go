@@ -0,0 +1,131 @@
+package rediscmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// keyless commands take no key arguments
+var keyless = map[string]bool{
+	"ping": true, "echo": true, "info": true, "auth": true, "select": true, "quit": true,
+	"hello": true, "client": true, "config": true, "dbsize": true, "flushdb": true,
+	"flushall": true, "time": true, "multi": true, "exec": true, "discard": true,
+	"unwatch": true, "script": true, "cluster": true, "command": true, "subscribe": true,
+	"psubscribe": true, "unsubscribe": true, "punsubscribe": true, "scan": true,
+	"randomkey": true, "save": true, "bgsave": true, "lastsave": true, "wait": true,
+}
+
+// multiKey commands take only keys as arguments
+var multiKey = map[string]bool{
+	"del": true, "exists": true, "mget": true, "touch": true, "unlink": true, "watch": true,
+	"sinter": true, "sunion": true, "sdiff": true, "pfcount": true,
+}
+
+// keyPositions returns the indexes of the key arguments of a command,
+// args[0] being the command name
+func keyPositions(args []any) []int {
+	if len(args) < 2 {
+		return nil
+	}
+	name := strings.ToLower(argString(args[0]))
+
+	switch {
+	case keyless[name]:
+		return nil
+	case multiKey[name]:
+		return positions(1, len(args), 1)
+	case name == "object" || name == "memory" || name == "xgroup" || name == "xinfo":
+		// the key follows the subcommand
+		if len(args) < 3 {
+			return nil
+		}
+		return []int{2}
+	case name == "mset" || name == "msetnx":
+		return positions(1, len(args), 2)
+	case name == "eval" || name == "evalsha" || name == "eval_ro" || name == "evalsha_ro" || name == "fcall":
+		if len(args) < 3 {
+			return nil
+		}
+		numKeys, err := strconv.Atoi(argString(args[2]))
+		if err != nil {
+			return nil
+		}
+		return positions(3, min(3+numKeys, len(args)), 1)
+	case name == "xread" || name == "xreadgroup":
+		for i, arg := range args {
+			if strings.EqualFold(argString(arg), "streams") {
+				streams := args[i+1:]
+				return positions(i+1, i+1+len(streams)/2, 1)
+			}
+		}
+		return nil
+	default:
+		return []int{1}
+	}
+}
+
+func positions(from int, to int, step int) []int {
+	var out []int
+	for i := from; i < to; i += step {
+		out = append(out, i)
+	}
+	return out
+}
+
+// KeyCount returns the number of keys a command operates on
+func KeyCount(args []any) int {
+	return len(keyPositions(args))
+}
+
+// Statement formats a command, replacing every argument but the command name
+// and the keys with ? unless raw is set
+func Statement(args []any, raw bool) string {
+	if len(args) == 0 {
+		return ""
+	}
+
+	keys := map[int]bool{}
+	for _, position := range keyPositions(args) {
+		keys[position] = true
+	}
+
+	var b strings.Builder
+	for i, arg := range args {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		switch {
+		case i == 0:
+			b.WriteString(strings.ToUpper(argString(arg)))
+		case raw || keys[i] || isSubcommand(args, i):
+			b.WriteString(argString(arg))
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// isSubcommand reports whether args[i] is the subcommand of a container command, e.g. CLIENT SETNAME
+func isSubcommand(args []any, i int) bool {
+	if i != 1 {
+		return false
+	}
+	switch strings.ToLower(argString(args[0])) {
+	case "client", "config", "cluster", "command", "script", "object", "memory", "xgroup", "xinfo":
+		return true
+	}
+	return false
+}
+
+func argString(arg any) string {
+	switch v := arg.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,287 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog"
+
+	"github.com/weeb-vip/go-tracing-lib/internal/logfields"
+	"github.com/weeb-vip/go-tracing-lib/providers"
+)
+
+// LevelEnv is the environment variable read for the default log level
+const LevelEnv = "LOG_LEVEL"
+
+// DefaultTraceFormats are the trace identifier formats integrations log when
+// none are configured, the Grafana and the Datadog ones
+var DefaultTraceFormats = logfields.DefaultFormats
+
+type ctxKey struct{}
+
+// ctxLogger is the logger attached to a context with the trace formats it is
+// known to log, see ContextWithTraceFormats
+type ctxLogger struct {
+	logger  zerolog.Logger
+	formats []providers.LogFormat
+}
+
+var mu sync.RWMutex
+var globalLogger = New()
+var globalFormats []providers.LogFormat
+
+// Format selects how log lines are written
+type Format int
+
+const (
+	// FormatJSON writes one JSON object per line
+	FormatJSON Format = iota
+	// FormatConsole writes human readable lines
+	FormatConsole
+)
+
+// New creates a logger instance configured with options
+func New(opts ...Option) zerolog.Logger {
+	return newConfig(opts).build()
+}
+
+func newConfig(opts []Option) *Config {
+	config := &Config{
+		ServiceName:    "unknown-service",
+		ServiceVersion: "unknown-version",
+		// the zerolog global logger wrapped by earlier versions logs every level
+		Level:  zerolog.TraceLevel,
+		Format: FormatJSON,
+		Output: os.Stderr,
+	}
+	if level, ok := levelFromEnv(LevelEnv); ok {
+		config.Level = level
+	}
+
+	for _, opt := range opts {
+		opt(config)
+	}
+	return config
+}
+
+func (config *Config) build() zerolog.Logger {
+	output := config.Output
+	if config.Format == FormatConsole {
+		output = zerolog.ConsoleWriter{Out: output}
+	}
+
+	l := zerolog.New(output).
+		Level(config.Level).
+		With().
+		Timestamp().
+		Str("service", config.ServiceName).
+		Str("version", config.ServiceVersion).
+		Logger()
+
+	if len(config.TraceFormats) > 0 {
+		l = l.Hook(NewTraceHook(config.TraceFormats...))
+	}
+	if config.SpanEvents != nil {
+		l = l.Hook(NewSpanHook(config.SpanEvents...))
+	}
+
+	return l
+}
+
+// Logger configures the global logger with options. It may be called again
+// to replace the global logger, e.g. between tests.
+func Logger(opts ...Option) {
+	config := newConfig(opts)
+	l := config.build()
+
+	mu.Lock()
+	defer mu.Unlock()
+	globalLogger = l
+	globalFormats = config.TraceFormats
+}
+
+// Reset restores the global logger to its defaults
+func Reset() {
+	Logger()
+}
+
+// Get returns the global logger instance
+func Get() zerolog.Logger {
+	mu.RLock()
+	defer mu.RUnlock()
+	return globalLogger
+}
+
+// FromCtx returns the Logger associated with the ctx. If no logger
+// is associated, the global logger is returned. The returned logger
+// is bound to ctx so trace hooks can read the active span.
+func FromCtx(ctx context.Context) zerolog.Logger {
+	l, ok := ctx.Value(ctxKey{}).(ctxLogger)
+	if !ok {
+		return Get().With().Ctx(ctx).Logger()
+	}
+	return l.logger.With().Ctx(ctx).Logger()
+}
+
+// FromContext is an alias for FromCtx for backward compatibility
+func FromContext(ctx context.Context) zerolog.Logger {
+	return FromCtx(ctx)
+}
+
+// WithCtx returns a copy of ctx with the Logger attached.
+func WithCtx(ctx context.Context, l zerolog.Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, ctxLogger{logger: l})
+}
+
+// WithContext returns a copy of ctx with the Logger attached.
+func WithContext(logger zerolog.Logger, ctx context.Context) context.Context {
+	return WithCtx(ctx, logger)
+}
+
+// ContextWithTraceFormats returns a copy of ctx whose logger logs trace
+// identifiers in formats, or in DefaultTraceFormats when none are given.
+// Formats the logger already logs are not added twice: those of the global
+// logger and of earlier calls on ctx. Loggers attached with WithContext are
+// assumed to log none.
+func ContextWithTraceFormats(ctx context.Context, formats ...providers.LogFormat) context.Context {
+	if len(formats) == 0 {
+		formats = DefaultTraceFormats
+	}
+
+	current, ok := ctx.Value(ctxKey{}).(ctxLogger)
+	if !ok {
+		mu.RLock()
+		current = ctxLogger{logger: globalLogger, formats: globalFormats}
+		mu.RUnlock()
+	}
+
+	var missing []providers.LogFormat
+	for _, format := range formats {
+		if !slices.Contains(current.formats, format) && !slices.Contains(missing, format) {
+			missing = append(missing, format)
+		}
+	}
+	if len(missing) == 0 {
+		return ctx
+	}
+
+	return context.WithValue(ctx, ctxKey{}, ctxLogger{
+		logger:  current.logger.Hook(NewTraceHook(missing...)),
+		formats: append(slices.Clip(current.formats), missing...),
+	})
+}
+
+// TraceHook adds the trace and span IDs of the event context in the configured formats
+type TraceHook struct {
+	formats []providers.LogFormat
+}
+
+// NewTraceHook creates a hook logging trace identifiers in formats
+func NewTraceHook(formats ...providers.LogFormat) *TraceHook {
+	return &TraceHook{formats: formats}
+}
+
+func (h *TraceHook) Run(e *zerolog.Event, level zerolog.Level, message string) {
+	for _, field := range logfields.FromContext(e.GetCtx(), h.formats) {
+		e.Str(field.Key, field.Value)
+	}
+}
+
+func levelFromEnv(name string) (zerolog.Level, bool) {
+	value, ok := os.LookupEnv(name)
+	if !ok || value == "" {
+		return zerolog.NoLevel, false
+	}
+	level, err := zerolog.ParseLevel(strings.ToLower(value))
+	if err != nil {
+		return zerolog.NoLevel, false
+	}
+	return level, true
+}
+
+// Config holds logger configuration
+type Config struct {
+	ServiceName    string
+	ServiceVersion string
+	Level          zerolog.Level
+	Format         Format
+	Output         io.Writer
+	TraceFormats   []providers.LogFormat
+	SpanEvents     []SpanHookOption
+}
+
+// Option configures the logger
+type Option func(*Config)
+
+// WithServerName sets the service name
+func WithServerName(name string) Option {
+	return func(c *Config) {
+		c.ServiceName = name
+	}
+}
+
+// WithVersion sets the service version
+func WithVersion(version string) Option {
+	return func(c *Config) {
+		c.ServiceVersion = version
+	}
+}
+
+// WithLevel sets the minimum level, overriding LOG_LEVEL
+func WithLevel(level zerolog.Level) Option {
+	return func(c *Config) {
+		c.Level = level
+	}
+}
+
+// WithLevelFromEnv reads the minimum level from the environment variable name,
+// the level is left unchanged if the variable is unset or invalid
+func WithLevelFromEnv(name string) Option {
+	return func(c *Config) {
+		if level, ok := levelFromEnv(name); ok {
+			c.Level = level
+		}
+	}
+}
+
+// WithFormat sets the output format
+func WithFormat(format Format) Option {
+	return func(c *Config) {
+		c.Format = format
+	}
+}
+
+// WithOutput sets the writer log lines are written to, defaults to stderr
+func WithOutput(w io.Writer) Option {
+	return func(c *Config) {
+		c.Output = w
+	}
+}
+
+// WithTraceFormats attaches a TraceHook logging trace identifiers in formats
+func WithTraceFormats(formats ...providers.LogFormat) Option {
+	return func(c *Config) {
+		c.TraceFormats = formats
+	}
+}
+
+// WithGrafana attaches a TraceHook logging trace identifiers as traceID and spanID
+func WithGrafana() Option {
+	return WithTraceFormats(providers.LogFormatGrafana)
+}
+
+// WithDatadog attaches a TraceHook logging trace identifiers as dd.trace_id and dd.span_id
+func WithDatadog() Option {
+	return WithTraceFormats(providers.LogFormatDatadog)
+}
+
+// WithSpanEvents records log events as events on the active span, see SpanHook
+func WithSpanEvents(opts ...SpanHookOption) Option {
+	return func(c *Config) {
+		c.SpanEvents = append([]SpanHookOption{}, opts...)
+	}
+}
@@ -0,0 +1,87 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/weeb-vip/go-tracing-lib/logger"
+	"github.com/weeb-vip/go-tracing-lib/providers"
+)
+
+func TestLogger(t *testing.T) {
+	t.Run("Should replace the global logger on every call", func(t *testing.T) {
+		a := assert.New(t)
+		defer logger.Reset()
+
+		first := &bytes.Buffer{}
+		logger.Logger(logger.WithOutput(first), logger.WithServerName("first"))
+		second := &bytes.Buffer{}
+		logger.Logger(logger.WithOutput(second), logger.WithServerName("second"))
+
+		l := logger.Get()
+		l.Info().Msg("hello")
+
+		a.Empty(first.String())
+		a.Contains(second.String(), `"service":"second"`)
+	})
+
+	t.Run("Should log every level by default", func(t *testing.T) {
+		a := assert.New(t)
+		t.Setenv(logger.LevelEnv, "")
+
+		a.Equal(zerolog.TraceLevel, logger.New().GetLevel())
+	})
+
+	t.Run("Should read the level from the environment", func(t *testing.T) {
+		a := assert.New(t)
+		t.Setenv(logger.LevelEnv, "WARN")
+
+		a.Equal(zerolog.WarnLevel, logger.New().GetLevel())
+		a.Equal(zerolog.DebugLevel, logger.New(logger.WithLevel(zerolog.DebugLevel)).GetLevel())
+	})
+
+	t.Run("Should add trace identifiers to loggers from the context", func(t *testing.T) {
+		a := assert.New(t)
+		buf := &bytes.Buffer{}
+		l := logger.New(logger.WithOutput(buf), logger.WithTraceFormats(providers.LogFormatDatadog))
+
+		ctx, span := trace.NewTracerProvider().Tracer("test").Start(context.Background(), "test")
+		defer span.End()
+		ctx = logger.WithContext(l, ctx)
+
+		l = logger.FromContext(ctx)
+		l.Info().Msg("hello")
+
+		fields := map[string]any{}
+		a.NoError(json.Unmarshal(buf.Bytes(), &fields))
+		a.Equal(span.SpanContext().TraceID().String(), fields["dd.trace_id"])
+		a.Equal(span.SpanContext().SpanID().String(), fields["dd.span_id"])
+		a.NotContains(fields, "traceID")
+	})
+
+	t.Run("Should not log trace identifiers twice", func(t *testing.T) {
+		a := assert.New(t)
+		defer logger.Reset()
+		buf := &bytes.Buffer{}
+		logger.Logger(logger.WithOutput(buf), logger.WithDatadog())
+
+		ctx, span := trace.NewTracerProvider().Tracer("test").Start(context.Background(), "test")
+		defer span.End()
+		ctx = logger.ContextWithTraceFormats(ctx)
+		ctx = logger.ContextWithTraceFormats(ctx, providers.LogFormatGrafana)
+
+		l := logger.FromContext(ctx)
+		l.Info().Msg("hello")
+
+		a.Equal(1, strings.Count(buf.String(), `"dd.trace_id"`))
+		a.Equal(1, strings.Count(buf.String(), `"traceID"`))
+		a.Equal(1, strings.Count(buf.String(), `"spanID"`))
+	})
+}
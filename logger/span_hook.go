@@ -0,0 +1,225 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SpanHook records log events on the span found in the event context, as span
+// events and, with WithErrorStatus, as the span error and status. It is a
+// plain zerolog hook and adds nothing to the log output:
+//
+//	l := zerolog.New(os.Stdout).Hook(logger.NewSpanHook())
+type SpanHook struct {
+	config spanHookConfig
+}
+
+type spanHookConfig struct {
+	minLevel       zerolog.Level
+	errorStatus    bool
+	errorLevel     zerolog.Level
+	maxFields      int
+	maxValueLength int
+}
+
+// SpanHookOption configures a SpanHook
+type SpanHookOption func(*spanHookConfig)
+
+// WithSpanEventLevel sets the minimum level recorded as a span event, defaults to debug.
+// zerolog.Disabled turns span events off, e.g. when only WithErrorStatus is wanted.
+func WithSpanEventLevel(level zerolog.Level) SpanHookOption {
+	return func(c *spanHookConfig) {
+		c.minLevel = level
+	}
+}
+
+// WithErrorStatus records the error of events at or above level, logged with
+// Err(err), on the span and sets the span status to Error
+func WithErrorStatus(level zerolog.Level) SpanHookOption {
+	return func(c *spanHookConfig) {
+		c.errorStatus = true
+		c.errorLevel = level
+	}
+}
+
+// WithMaxFields limits how many log fields become span event attributes, defaults to 32
+func WithMaxFields(n int) SpanHookOption {
+	return func(c *spanHookConfig) {
+		c.maxFields = n
+	}
+}
+
+// WithMaxValueLength truncates the message and string field values, defaults to 1024 bytes
+func WithMaxValueLength(n int) SpanHookOption {
+	return func(c *spanHookConfig) {
+		c.maxValueLength = n
+	}
+}
+
+// NewSpanHook creates a SpanHook configured with options
+func NewSpanHook(opts ...SpanHookOption) *SpanHook {
+	config := spanHookConfig{
+		minLevel:       zerolog.DebugLevel,
+		maxFields:      32,
+		maxValueLength: 1024,
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	if errEventBuf != nil {
+		otel.Handle(errEventBuf)
+	}
+	return &SpanHook{config: config}
+}
+
+func (h *SpanHook) Run(e *zerolog.Event, level zerolog.Level, message string) {
+	if level == zerolog.NoLevel || level == zerolog.Disabled {
+		return
+	}
+	addEvent := level >= h.config.minLevel
+	errorStatus := h.config.errorStatus && level >= h.config.errorLevel
+	if !addEvent && !errorStatus {
+		return
+	}
+	span := trace.SpanFromContext(e.GetCtx())
+	if !span.IsRecording() {
+		return
+	}
+
+	fields := eventFields(e)
+	if errorStatus {
+		h.recordError(span, message, fields)
+	}
+	if addEvent {
+		h.addEvent(span, level, message, fields)
+	}
+}
+
+// eventBuf locates the unexported buffer holding the fields of a zerolog
+// event. zerolog offers no way for hooks to read them, so the layout is
+// checked once against the zerolog version in go.mod and a mismatch is
+// reported by NewSpanHook instead of silently recording no fields.
+var eventBuf, errEventBuf = eventBufferField()
+
+func eventBufferField() ([]int, error) {
+	field, ok := reflect.TypeOf((*zerolog.Event)(nil)).Elem().FieldByName("buf")
+	if !ok || field.Type != reflect.TypeOf([]byte(nil)) {
+		return nil, errors.New("logger: zerolog.Event has no buf []byte field, SpanHook records no log fields or errors")
+	}
+	return field.Index, nil
+}
+
+// eventFields decodes the fields written to e so far. zerolog hooks run
+// before the message is appended, so the buffer is a JSON object missing its
+// closing brace. Nothing is returned for the binary_log encoding.
+func eventFields(e *zerolog.Event) map[string]any {
+	if errEventBuf != nil {
+		return nil
+	}
+	buf := reflect.ValueOf(e).Elem().FieldByIndex(eventBuf)
+	data := append(append(make([]byte, 0, buf.Len()+1), buf.Bytes()...), '}')
+
+	fields := map[string]any{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&fields); err != nil {
+		return nil
+	}
+	return fields
+}
+
+// recordError records the error field of the event on span, zerolog only
+// keeps the text of the error so that is what is recorded
+func (h *SpanHook) recordError(span trace.Span, message string, fields map[string]any) {
+	value, ok := fields[zerolog.ErrorFieldName]
+	if !ok || value == nil {
+		return
+	}
+	text, ok := value.(string)
+	if !ok {
+		// errors marshalled as objects by zerolog.ErrorMarshalFunc
+		b, _ := json.Marshal(value)
+		text = string(b)
+	}
+	if text == "" {
+		return
+	}
+	span.RecordError(errors.New(text))
+
+	description := text
+	if message != "" {
+		description = message + ": " + text
+	}
+	span.SetStatus(codes.Error, h.truncate(description))
+}
+
+func (h *SpanHook) addEvent(span trace.Span, level zerolog.Level, message string, fields map[string]any) {
+	attrs := []attribute.KeyValue{
+		attribute.String("log.severity", level.String()),
+		attribute.String("log.message", h.truncate(message)),
+	}
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		switch key {
+		case zerolog.LevelFieldName, zerolog.MessageFieldName, zerolog.TimestampFieldName:
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	if len(keys) > h.config.maxFields {
+		keys = keys[:h.config.maxFields]
+		attrs = append(attrs, attribute.Bool("log.fields_truncated", true))
+	}
+	for _, key := range keys {
+		attrs = append(attrs, h.attribute(key, fields[key]))
+	}
+
+	name := message
+	if name == "" {
+		name = "log"
+	}
+	span.AddEvent(h.truncate(name), trace.WithAttributes(attrs...))
+}
+
+func (h *SpanHook) attribute(key string, value any) attribute.KeyValue {
+	switch v := value.(type) {
+	case string:
+		return attribute.String(key, h.truncate(v))
+	case bool:
+		return attribute.Bool(key, v)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return attribute.Int64(key, i)
+		}
+		if f, err := v.Float64(); err == nil {
+			return attribute.Float64(key, f)
+		}
+		return attribute.String(key, v.String())
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return attribute.String(key, h.truncate(fmt.Sprint(v)))
+		}
+		return attribute.String(key, h.truncate(string(b)))
+	}
+}
+
+func (h *SpanHook) truncate(s string) string {
+	if h.config.maxValueLength > 0 && len(s) > h.config.maxValueLength {
+		return s[:h.config.maxValueLength]
+	}
+	return s
+}
@@ -0,0 +1,161 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/weeb-vip/go-tracing-lib/logger"
+)
+
+type codedError struct {
+	err  error
+	code int
+}
+
+func (e codedError) MarshalZerologObject(event *zerolog.Event) {
+	event.Str("message", e.err.Error()).Int("code", e.code)
+}
+
+func TestSpanHook(t *testing.T) {
+	t.Run("Should read the fields of zerolog events", func(t *testing.T) {
+		a := assert.New(t)
+		var errs []error
+		defer otel.SetErrorHandler(otel.GetErrorHandler())
+		otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) { errs = append(errs, err) }))
+
+		logger.NewSpanHook()
+
+		// SpanHook reads zerolog internals, an upgrade changing them fails here
+		a.Empty(errs, "check SpanHook against the zerolog version in go.mod")
+	})
+
+	t.Run("Should record log events on the active span", func(t *testing.T) {
+		a := assert.New(t)
+		recorder := tracetest.NewSpanRecorder()
+		tracer := trace.NewTracerProvider(trace.WithSpanProcessor(recorder)).Tracer("test")
+
+		buf := &bytes.Buffer{}
+		l := logger.New(logger.WithOutput(buf), logger.WithSpanEvents(logger.WithSpanEventLevel(zerolog.InfoLevel)))
+
+		ctx, span := tracer.Start(context.Background(), "test")
+		l.Info().Ctx(ctx).Str("user", "alice").Int("count", 3).Msg("hello")
+		l.Debug().Ctx(ctx).Msg("filtered")
+		span.End()
+
+		a.NotContains(buf.String(), "_otel_span_hook")
+		a.Contains(buf.String(), `"user":"alice","count":3,"time":`)
+
+		events := recorder.Ended()[0].Events()
+		a.Len(events, 1)
+		a.Equal("hello", events[0].Name)
+		a.Contains(events[0].Attributes, attribute.String("log.severity", "info"))
+		a.Contains(events[0].Attributes, attribute.String("user", "alice"))
+		a.Contains(events[0].Attributes, attribute.Int64("count", 3))
+	})
+
+	t.Run("Should limit the recorded fields", func(t *testing.T) {
+		a := assert.New(t)
+		recorder := tracetest.NewSpanRecorder()
+		tracer := trace.NewTracerProvider(trace.WithSpanProcessor(recorder)).Tracer("test")
+
+		hook := logger.NewSpanHook(logger.WithMaxFields(1), logger.WithMaxValueLength(3))
+		buf := &bytes.Buffer{}
+		l := zerolog.New(buf).Hook(hook)
+
+		ctx, span := tracer.Start(context.Background(), "test")
+		l.Info().Ctx(ctx).Str("a", "abcdef").Str("b", "ignored").Msg("")
+		span.End()
+
+		a.Equal(`{"level":"info","a":"abcdef","b":"ignored"}`+"\n", buf.String())
+
+		attrs := recorder.Ended()[0].Events()[0].Attributes
+		a.Contains(attrs, attribute.String("a", "abc"))
+		a.Contains(attrs, attribute.Bool("log.fields_truncated", true))
+		a.NotContains(attrs, attribute.String("b", "ignored"))
+	})
+
+	t.Run("Should record events when attached as a plain hook", func(t *testing.T) {
+		a := assert.New(t)
+		recorder := tracetest.NewSpanRecorder()
+		tracer := trace.NewTracerProvider(trace.WithSpanProcessor(recorder)).Tracer("test")
+
+		buf := &bytes.Buffer{}
+		l := zerolog.New(buf).With().Timestamp().Str("service", "users").Logger().Hook(logger.NewSpanHook())
+
+		ctx, span := tracer.Start(context.Background(), "test")
+		l.Info().Ctx(ctx).Str("user", "alice").Msg("hello")
+		l.Info().Msg("no span")
+		span.End()
+
+		a.NotContains(buf.String(), "_otel")
+		a.Equal(2, bytes.Count(buf.Bytes(), []byte("\n")))
+
+		events := recorder.Ended()[0].Events()
+		a.Len(events, 1)
+		a.Equal("hello", events[0].Name)
+		a.Contains(events[0].Attributes, attribute.String("service", "users"))
+		a.Contains(events[0].Attributes, attribute.String("user", "alice"))
+	})
+
+	t.Run("Should flag the span when an error is logged", func(t *testing.T) {
+		a := assert.New(t)
+		recorder := tracetest.NewSpanRecorder()
+		tracer := trace.NewTracerProvider(trace.WithSpanProcessor(recorder)).Tracer("test")
+
+		l := logger.New(logger.WithOutput(&bytes.Buffer{}), logger.WithSpanEvents(
+			logger.WithSpanEventLevel(zerolog.Disabled),
+			logger.WithErrorStatus(zerolog.ErrorLevel),
+		))
+
+		ctx, span := tracer.Start(context.Background(), "test")
+		l.Warn().Ctx(ctx).Err(errors.New("ignored")).Msg("retrying")
+		l.Error().Ctx(ctx).Msg("no error field")
+		a.Equal(codes.Unset, recorder.Started()[0].(trace.ReadOnlySpan).Status().Code)
+
+		l.Error().Ctx(ctx).Err(errors.New("connection refused")).Msg("failed to load user")
+		span.End()
+
+		ended := recorder.Ended()[0]
+		a.Equal(codes.Error, ended.Status().Code)
+		a.Equal("failed to load user: connection refused", ended.Status().Description)
+		a.Len(ended.Events(), 1)
+		a.Equal("exception", ended.Events()[0].Name)
+		a.Contains(ended.Events()[0].Attributes, attribute.String("exception.message", "connection refused"))
+	})
+
+	t.Run("Should record the logged error when attached as a plain hook", func(t *testing.T) {
+		a := assert.New(t)
+		recorder := tracetest.NewSpanRecorder()
+		tracer := trace.NewTracerProvider(trace.WithSpanProcessor(recorder)).Tracer("test")
+
+		marshal := zerolog.ErrorMarshalFunc
+		defer func() { zerolog.ErrorMarshalFunc = marshal }()
+		zerolog.ErrorMarshalFunc = func(err error) interface{} {
+			return codedError{err: err, code: 503}
+		}
+
+		l := zerolog.New(&bytes.Buffer{}).Hook(logger.NewSpanHook(
+			logger.WithSpanEventLevel(zerolog.Disabled),
+			logger.WithErrorStatus(zerolog.ErrorLevel),
+		))
+
+		ctx, span := tracer.Start(context.Background(), "test")
+		l.Error().Ctx(ctx).Err(errors.New("unavailable")).Msg("failed to call users")
+		span.End()
+
+		ended := recorder.Ended()[0]
+		a.Equal(codes.Error, ended.Status().Code)
+		a.Equal(`failed to call users: {"code":503,"message":"unavailable"}`, ended.Status().Description)
+		a.Contains(ended.Events()[0].Attributes, attribute.String("exception.message", `{"code":503,"message":"unavailable"}`))
+	})
+}
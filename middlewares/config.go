@@ -0,0 +1,85 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/weeb-vip/go-tracing-lib/logger"
+	"github.com/weeb-vip/go-tracing-lib/providers"
+	"github.com/weeb-vip/go-tracing-lib/tracing/inbound"
+)
+
+const instrumentationName = "github.com/weeb-vip/go-tracing-lib/middlewares"
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	propagator     propagation.TextMapPropagator
+	serverName     string
+	logFormats     []providers.LogFormat
+	boundary       *inbound.Boundary
+}
+
+// Option configures the middleware
+type Option func(*config)
+
+// WithTracerProvider sets the tracer provider, defaults to the global provider
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = provider
+	}
+}
+
+// WithPropagator sets the propagator used to extract the incoming context,
+// defaults to the global propagator
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagator = propagator
+	}
+}
+
+// WithServerName sets the net.host.name attribute instead of the request host
+func WithServerName(name string) Option {
+	return func(c *config) {
+		c.serverName = name
+	}
+}
+
+// WithLogFormats selects the trace identifier formats of the request logger,
+// defaults to logger.DefaultTraceFormats
+func WithLogFormats(formats ...providers.LogFormat) Option {
+	return func(c *config) {
+		c.logFormats = formats
+	}
+}
+
+// WithTrustBoundary only continues the trace of requests the boundary trusts,
+// untrusted requests start a new trace
+func WithTrustBoundary(boundary *inbound.Boundary) Option {
+	return func(c *config) {
+		c.boundary = boundary
+	}
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{
+		tracerProvider: otel.GetTracerProvider(),
+		propagator:     otel.GetTextMapPropagator(),
+		logFormats:     logger.DefaultTraceFormats,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// extract returns ctx with the trace context of r, or the part of it the trust boundary accepts
+func (c *config) extract(ctx context.Context, r *http.Request) (context.Context, inbound.Incoming) {
+	if c.boundary == nil {
+		return c.propagator.Extract(ctx, propagation.HeaderCarrier(r.Header)), inbound.Incoming{}
+	}
+	return c.boundary.Extract(ctx, c.propagator, r)
+}
@@ -0,0 +1,75 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	"go.opentelemetry.io/otel/semconv/v1.20.0/httpconv"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/weeb-vip/go-tracing-lib/logger"
+)
+
+// TracingMiddleware starts a server span for every request, continuing the
+// trace found in the request headers, and attaches a trace-aware logger to
+// the request context. See WithTrustBoundary for public endpoints.
+func TracingMiddleware(opts ...Option) gin.HandlerFunc {
+	config := newConfig(opts)
+	tracer := config.tracerProvider.Tracer(instrumentationName)
+
+	return func(c *gin.Context) {
+		ctx, incoming := config.extract(c.Request.Context(), c.Request)
+
+		route := c.FullPath()
+		attrs := httpconv.ServerRequest(config.serverName, c.Request)
+		if route != "" {
+			attrs = append(attrs, semconv.HTTPRoute(route))
+		}
+
+		startOpts := append([]trace.SpanStartOption{
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(attrs...),
+		}, incoming.StartOptions()...)
+		ctx, span := tracer.Start(ctx, spanName(c.Request.Method, route), startOpts...)
+		defer span.End()
+
+		// attach tracer context to logger
+		ctx = logger.ContextWithTraceFormats(ctx, config.logFormats...)
+		c.Request = c.Request.WithContext(ctx)
+
+		defer func() {
+			if r := recover(); r != nil {
+				span.RecordError(fmt.Errorf("panic: %v", r), trace.WithStackTrace(true))
+				span.SetStatus(codes.Error, fmt.Sprint(r))
+				span.SetAttributes(semconv.HTTPStatusCode(http.StatusInternalServerError))
+				panic(r)
+			}
+		}()
+
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(semconv.HTTPStatusCode(status))
+		if size := c.Writer.Size(); size > 0 {
+			span.SetAttributes(semconv.HTTPResponseContentLength(size))
+		}
+		span.SetStatus(httpconv.ServerStatus(status))
+
+		if len(c.Errors) > 0 {
+			for _, err := range c.Errors {
+				span.RecordError(err.Err)
+			}
+			span.SetStatus(codes.Error, c.Errors.String())
+		}
+	}
+}
+
+func spanName(method string, route string) string {
+	if route == "" {
+		return fmt.Sprintf("HTTP %s", method)
+	}
+	return method + " " + route
+}
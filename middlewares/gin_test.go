@@ -0,0 +1,131 @@
+package middlewares_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/weeb-vip/go-tracing-lib/logger"
+	"github.com/weeb-vip/go-tracing-lib/middlewares"
+	"github.com/weeb-vip/go-tracing-lib/tracing/inbound"
+)
+
+func newRouter(recorder *tracetest.SpanRecorder) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(gin.CustomRecovery(func(c *gin.Context, _ any) {
+		c.AbortWithStatus(http.StatusInternalServerError)
+	}))
+	router.Use(middlewares.TracingMiddleware(
+		middlewares.WithTracerProvider(trace.NewTracerProvider(trace.WithSpanProcessor(recorder))),
+		middlewares.WithPropagator(propagation.TraceContext{}),
+	))
+	return router
+}
+
+func TestTracingMiddleware(t *testing.T) {
+	t.Run("Should start a server span named by the route", func(t *testing.T) {
+		a := assert.New(t)
+		buf := &bytes.Buffer{}
+		logger.Logger(logger.WithOutput(buf))
+		defer logger.Reset()
+
+		recorder := tracetest.NewSpanRecorder()
+		router := newRouter(recorder)
+		router.GET("/users/:id", func(c *gin.Context) {
+			l := logger.FromContext(c.Request.Context())
+			l.Info().Msg("loading user")
+			c.String(http.StatusCreated, "created")
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+		req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+
+		span := recorder.Ended()[0]
+		a.Equal("GET /users/:id", span.Name())
+		a.Equal("4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+		a.Equal("00f067aa0ba902b7", span.Parent().SpanID().String())
+		a.Contains(span.Attributes(), attribute.Int("http.status_code", http.StatusCreated))
+		a.Contains(span.Attributes(), attribute.Int("http.response_content_length", len("created")))
+		a.Equal(codes.Unset, span.Status().Code)
+		a.Contains(buf.String(), `"traceID":"4bf92f3577b34da6a3ce929d0e0e4736"`)
+		a.Contains(buf.String(), `"dd.span_id":"`+span.SpanContext().SpanID().String()+`"`)
+	})
+
+	t.Run("Should record handler errors", func(t *testing.T) {
+		a := assert.New(t)
+		recorder := tracetest.NewSpanRecorder()
+		router := newRouter(recorder)
+		router.GET("/fail", func(c *gin.Context) {
+			_ = c.Error(errors.New("boom"))
+			c.Status(http.StatusBadRequest)
+		})
+
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/fail", nil))
+
+		span := recorder.Ended()[0]
+		a.Equal(codes.Error, span.Status().Code)
+		a.Equal("exception", span.Events()[0].Name)
+	})
+
+	t.Run("Should name unmatched requests after the method", func(t *testing.T) {
+		a := assert.New(t)
+		recorder := tracetest.NewSpanRecorder()
+		router := newRouter(recorder)
+
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/missing", nil))
+
+		a.Equal("HTTP POST", recorder.Ended()[0].Name())
+	})
+
+	t.Run("Should record panics", func(t *testing.T) {
+		a := assert.New(t)
+		recorder := tracetest.NewSpanRecorder()
+		router := newRouter(recorder)
+		router.GET("/panic", func(c *gin.Context) {
+			panic("boom")
+		})
+
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+		span := recorder.Ended()[0]
+		a.Equal(codes.Error, span.Status().Code)
+		a.Contains(span.Attributes(), attribute.Int("http.status_code", http.StatusInternalServerError))
+	})
+}
+
+func TestTrustBoundary(t *testing.T) {
+	t.Run("Should link instead of continuing untrusted traces", func(t *testing.T) {
+		a := assert.New(t)
+		gin.SetMode(gin.TestMode)
+		recorder := tracetest.NewSpanRecorder()
+		router := gin.New()
+		router.Use(middlewares.TracingMiddleware(
+			middlewares.WithTracerProvider(trace.NewTracerProvider(trace.WithSpanProcessor(recorder))),
+			middlewares.WithPropagator(propagation.TraceContext{}),
+			middlewares.WithTrustBoundary(inbound.New()),
+		))
+		router.GET("/users/:id", func(c *gin.Context) {})
+
+		req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+		req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+
+		span := recorder.Ended()[0]
+		a.False(span.Parent().IsValid())
+		a.Len(span.Links(), 1)
+		a.Equal("4bf92f3577b34da6a3ce929d0e0e4736", span.Links()[0].SpanContext.TraceID().String())
+		a.Contains(span.Attributes(), attribute.Bool("trace.inbound.trusted", false))
+	})
+}
@@ -5,10 +5,14 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 
-	"github.com/weeb-vip/go-tracing-lib/internal/logger"
+	"github.com/weeb-vip/go-tracing-lib/logger"
 	"github.com/weeb-vip/go-tracing-lib/providers/datadog"
 )
 
+// DatadogTracingMiddleware decorates the span started by another middleware.
+//
+// Deprecated: use middlewares.TracingMiddleware, which starts the server span itself
+// and works with every provider.
 func DatadogTracingMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// attach tracer context to logger
@@ -19,8 +23,9 @@ func DatadogTracingMiddleware() gin.HandlerFunc {
 		// set tracing attributes for datadog
 		span := trace.SpanFromContext(c.Request.Context())
 		span.SetAttributes(attribute.String("http.method", c.Request.Method))
-		defer span.SetAttributes(attribute.Int("http.status_code", c.Writer.Status()))
 
 		c.Next()
+
+		span.SetAttributes(attribute.Int("http.status_code", c.Writer.Status()))
 	}
 }
@@ -10,18 +10,17 @@ import (
 	ddtracer "gopkg.in/DataDog/dd-trace-go.v1/ddtrace/tracer"
 
 	"github.com/weeb-vip/go-tracing-lib/providers"
-	"github.com/weeb-vip/go-tracing-lib/internal/logger"
+	"github.com/weeb-vip/go-tracing-lib/logger"
 )
 
 func NewProvider(ctx context.Context, config providers.ProviderConfig, ddLogger ddtrace.Logger) (trace.TracerProvider, func(ctx context.Context) error) {
 	if ddLogger == nil {
-		logger.Logger(
+		log := logger.New(
 			logger.WithVersion(config.ServiceVersion),
 			logger.WithServerName(config.ServiceName),
+			logger.WithDatadog(),
 		)
 
-		log := logger.Get()
-
 		ddLogger = NewDefaultLogger(&log)
 	}
 
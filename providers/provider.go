@@ -4,3 +4,13 @@ type ProviderConfig struct {
 	ServiceName    string
 	ServiceVersion string
 }
+
+// LogFormat selects the field names used when trace identifiers are added to logs
+type LogFormat int
+
+const (
+	// LogFormatGrafana logs trace identifiers as traceID and spanID
+	LogFormatGrafana LogFormat = iota
+	// LogFormatDatadog logs trace identifiers as dd.trace_id and dd.span_id
+	LogFormatDatadog
+)
@@ -0,0 +1,197 @@
+package inbound
+
+import (
+	"context"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// TrustedKey tells whether the trace context of a request was trusted, it
+	// is set on server spans of requests carrying one and on links to the
+	// untrusted span context
+	TrustedKey = attribute.Key("trace.inbound.trusted")
+	// BaggageDroppedKey is the number of baggage members dropped to enforce the limits
+	BaggageDroppedKey = attribute.Key("trace.inbound.baggage_dropped")
+)
+
+// Action is what happens to the trace context of untrusted requests
+type Action int
+
+const (
+	// Restart starts a new trace linked to the incoming span context
+	Restart Action = iota
+	// Drop starts a new trace and ignores the incoming span context
+	Drop
+)
+
+// Boundary decides which requests may continue their trace. Requests are
+// trusted when they come from a trusted network or carry a trusted header.
+// Untrusted requests start a new trace and their baggage is dropped, so
+// callers cannot force sampling or inject baggage.
+type Boundary struct {
+	networks       []netip.Prefix
+	headers        map[string][]string
+	untrusted      Action
+	maxMembers     int
+	maxBaggageSize int
+}
+
+// Option configures a boundary
+type Option func(*Boundary)
+
+// TrustNetworks trusts requests whose remote address is in one of networks.
+// Behind a proxy the remote address is the one of the proxy.
+func TrustNetworks(networks ...netip.Prefix) Option {
+	return func(b *Boundary) {
+		b.networks = append(b.networks, networks...)
+	}
+}
+
+// TrustHeader trusts requests with the header set to one of values, or to
+// any value when none are given. The header must be removed from external
+// requests by the edge proxy for this to be safe.
+func TrustHeader(name string, values ...string) Option {
+	return func(b *Boundary) {
+		b.headers[http.CanonicalHeaderKey(name)] = values
+	}
+}
+
+// WithUntrusted sets what happens to the trace context of untrusted requests, defaults to Restart
+func WithUntrusted(action Action) Option {
+	return func(b *Boundary) {
+		b.untrusted = action
+	}
+}
+
+// WithBaggageLimits caps the members and the encoded size in bytes of the
+// baggage of trusted requests, defaults to 64 members and 8192 bytes
+func WithBaggageLimits(members int, size int) Option {
+	return func(b *Boundary) {
+		b.maxMembers = members
+		b.maxBaggageSize = size
+	}
+}
+
+// New creates a boundary, without TrustNetworks or TrustHeader no request is trusted
+func New(opts ...Option) *Boundary {
+	b := &Boundary{
+		headers:        map[string][]string{},
+		untrusted:      Restart,
+		maxMembers:     64,
+		maxBaggageSize: 8192,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// Incoming describes the trace context accepted from a request, to be added to its server span
+type Incoming struct {
+	Links      []trace.Link
+	Attributes []attribute.KeyValue
+}
+
+// StartOptions returns the options adding the links and attributes to the
+// server span, they are passed at start so that samplers see them
+func (i Incoming) StartOptions() []trace.SpanStartOption {
+	return []trace.SpanStartOption{trace.WithLinks(i.Links...), trace.WithAttributes(i.Attributes...)}
+}
+
+// Extract returns ctx with the trace context of r the boundary accepts
+func (b *Boundary) Extract(ctx context.Context, propagator propagation.TextMapPropagator, r *http.Request) (context.Context, Incoming) {
+	extracted := propagator.Extract(ctx, propagation.HeaderCarrier(r.Header))
+	external := trace.SpanContextFromContext(extracted)
+	bag := baggage.FromContext(extracted)
+	if !external.IsValid() && bag.Len() == 0 {
+		return ctx, Incoming{}
+	}
+
+	if !b.Trusted(r) {
+		incoming := Incoming{Attributes: []attribute.KeyValue{TrustedKey.Bool(false)}}
+		if b.untrusted == Restart && external.IsValid() {
+			incoming.Links = append(incoming.Links, trace.Link{
+				SpanContext: external,
+				Attributes:  []attribute.KeyValue{TrustedKey.Bool(false)},
+			})
+		}
+		return ctx, incoming
+	}
+
+	incoming := Incoming{Attributes: []attribute.KeyValue{TrustedKey.Bool(true)}}
+	limited, dropped := b.limit(bag)
+	if dropped > 0 {
+		incoming.Attributes = append(incoming.Attributes, BaggageDroppedKey.Int(dropped))
+	}
+	return baggage.ContextWithBaggage(extracted, limited), incoming
+}
+
+// Trusted reports whether r comes from a trusted network or carries a trusted header
+func (b *Boundary) Trusted(r *http.Request) bool {
+	for name, allowed := range b.headers {
+		if value := r.Header.Get(name); value != "" && (len(allowed) == 0 || slices.Contains(allowed, value)) {
+			return true
+		}
+	}
+	if len(b.networks) == 0 {
+		return false
+	}
+	addr, ok := remoteAddr(r.RemoteAddr)
+	if !ok {
+		return false
+	}
+	for _, network := range b.networks {
+		if network.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// limit keeps the members of bag, in key order, that fit in the limits and
+// returns how many were dropped
+func (b *Boundary) limit(bag baggage.Baggage) (baggage.Baggage, int) {
+	members := bag.Members()
+	if len(members) == 0 {
+		return bag, 0
+	}
+	slices.SortFunc(members, func(x, y baggage.Member) int {
+		return strings.Compare(x.Key(), y.Key())
+	})
+
+	var kept []baggage.Member
+	size := 0
+	for _, member := range members {
+		// members are separated by commas
+		memberSize := len(member.String())
+		if len(kept) > 0 {
+			memberSize++
+		}
+		if len(kept) == b.maxMembers || size+memberSize > b.maxBaggageSize {
+			continue
+		}
+		kept = append(kept, member)
+		size += memberSize
+	}
+	// the members come from a valid baggage
+	limited, _ := baggage.New(kept...)
+	return limited, len(members) - len(kept)
+}
+
+func remoteAddr(remote string) (netip.Addr, bool) {
+	if addrPort, err := netip.ParseAddrPort(remote); err == nil {
+		return addrPort.Addr().Unmap(), true
+	}
+	if addr, err := netip.ParseAddr(remote); err == nil {
+		return addr.Unmap(), true
+	}
+	return netip.Addr{}, false
+}
@@ -0,0 +1,89 @@
+package inbound_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/weeb-vip/go-tracing-lib/tracing/inbound"
+)
+
+var propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+func request(remoteAddr string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.RemoteAddr = remoteAddr
+	r.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	r.Header.Set("baggage", "user.id=42,tenant=acme,plan=free")
+	return r
+}
+
+func TestBoundary(t *testing.T) {
+	boundary := inbound.New(
+		inbound.TrustNetworks(netip.MustParsePrefix("10.0.0.0/8")),
+		inbound.TrustHeader("X-Internal-Gateway", "edge"),
+		inbound.WithBaggageLimits(2, 8192),
+	)
+
+	t.Run("Should continue the trace of trusted requests within the baggage limits", func(t *testing.T) {
+		a := assert.New(t)
+
+		ctx, incoming := boundary.Extract(context.Background(), propagator, request("10.1.2.3:4321"))
+
+		a.Equal("4bf92f3577b34da6a3ce929d0e0e4736", trace.SpanContextFromContext(ctx).TraceID().String())
+		bag := baggage.FromContext(ctx)
+		a.Equal(2, bag.Len())
+		a.Equal("free", bag.Member("plan").Value())
+		a.Equal("acme", bag.Member("tenant").Value())
+		a.Empty(incoming.Links)
+		a.Contains(incoming.Attributes, attribute.Bool("trace.inbound.trusted", true))
+		a.Contains(incoming.Attributes, attribute.Int("trace.inbound.baggage_dropped", 1))
+	})
+
+	t.Run("Should trust requests with a trusted header", func(t *testing.T) {
+		a := assert.New(t)
+		r := request("203.0.113.1:4321")
+		r.Header.Set("X-Internal-Gateway", "edge")
+
+		a.True(boundary.Trusted(r))
+		r.Header.Set("X-Internal-Gateway", "other")
+		a.False(boundary.Trusted(r))
+	})
+
+	t.Run("Should restart the trace of untrusted requests with a link", func(t *testing.T) {
+		a := assert.New(t)
+
+		ctx, incoming := boundary.Extract(context.Background(), propagator, request("203.0.113.1:4321"))
+
+		a.False(trace.SpanContextFromContext(ctx).IsValid())
+		a.Equal(0, baggage.FromContext(ctx).Len())
+		a.Len(incoming.Links, 1)
+		a.Equal("00f067aa0ba902b7", incoming.Links[0].SpanContext.SpanID().String())
+		a.Contains(incoming.Attributes, attribute.Bool("trace.inbound.trusted", false))
+	})
+
+	t.Run("Should drop the context of untrusted requests", func(t *testing.T) {
+		a := assert.New(t)
+
+		ctx, incoming := inbound.New(inbound.WithUntrusted(inbound.Drop)).Extract(context.Background(), propagator, request("10.1.2.3:4321"))
+
+		a.False(trace.SpanContextFromContext(ctx).IsValid())
+		a.Empty(incoming.Links)
+	})
+
+	t.Run("Should leave requests without trace context alone", func(t *testing.T) {
+		a := assert.New(t)
+
+		_, incoming := boundary.Extract(context.Background(), propagator, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		a.Empty(incoming.Attributes)
+	})
+}
@@ -0,0 +1,132 @@
+package outbound
+
+import (
+	"context"
+	"path"
+
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Rule is the trace context a destination may receive
+type Rule struct {
+	// TraceParent sends the span context, e.g. the traceparent header
+	TraceParent bool
+	// TraceState sends the vendor trace state along the span context
+	TraceState bool
+	// Baggage lists the baggage keys sent, "*" sends every key
+	Baggage []string
+}
+
+var (
+	// All sends the whole trace context
+	All = Rule{TraceParent: true, TraceState: true, Baggage: []string{"*"}}
+	// None sends no trace context
+	None = Rule{}
+)
+
+type destinationRule struct {
+	pattern string
+	rule    Rule
+}
+
+// Policy decides which trace context is propagated to each destination, a
+// host for HTTP requests and an exchange or stream for messages
+type Policy struct {
+	rules    []destinationRule
+	fallback Rule
+}
+
+// Option configures a policy
+type Option func(*Policy)
+
+// Allow applies rule to the destinations matching pattern, see path.Match
+// for the syntax, e.g. "*.svc.cluster.local". The first matching rule wins.
+func Allow(pattern string, rule Rule) Option {
+	return func(p *Policy) {
+		p.rules = append(p.rules, destinationRule{pattern: pattern, rule: rule})
+	}
+}
+
+// WithDefault sets the rule of destinations matching no pattern, defaults to None
+func WithDefault(rule Rule) Option {
+	return func(p *Policy) {
+		p.fallback = rule
+	}
+}
+
+// New creates a policy, destinations without a rule receive no trace context unless WithDefault is set
+func New(opts ...Option) *Policy {
+	p := &Policy{fallback: None}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Rule returns the rule of destination
+func (p *Policy) Rule(destination string) Rule {
+	for _, r := range p.rules {
+		if matched, _ := path.Match(r.pattern, destination); matched {
+			return r.rule
+		}
+	}
+	return p.fallback
+}
+
+// Propagator returns a propagator injecting with base only the trace context
+// the rule of destination allows. Extraction is left to base.
+func (p *Policy) Propagator(destination string, base propagation.TextMapPropagator) propagation.TextMapPropagator {
+	return &filtered{base: base, rule: p.Rule(destination)}
+}
+
+type filtered struct {
+	base propagation.TextMapPropagator
+	rule Rule
+}
+
+var _ propagation.TextMapPropagator = &filtered{}
+
+func (f *filtered) Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	f.base.Inject(f.rule.apply(ctx), carrier)
+}
+
+func (f *filtered) Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	return f.base.Extract(ctx, carrier)
+}
+
+func (f *filtered) Fields() []string {
+	return f.base.Fields()
+}
+
+// apply returns ctx with the span context and baggage the rule allows
+func (r Rule) apply(ctx context.Context) context.Context {
+	sc := trace.SpanContextFromContext(ctx)
+	switch {
+	case !r.TraceParent:
+		sc = trace.SpanContext{}
+	case !r.TraceState:
+		sc = sc.WithTraceState(trace.TraceState{})
+	}
+	ctx = trace.ContextWithSpanContext(ctx, sc)
+
+	var members []baggage.Member
+	for _, member := range baggage.FromContext(ctx).Members() {
+		if r.allowsBaggage(member.Key()) {
+			members = append(members, member)
+		}
+	}
+	// the members come from a valid baggage
+	filtered, _ := baggage.New(members...)
+	return baggage.ContextWithBaggage(ctx, filtered)
+}
+
+func (r Rule) allowsBaggage(key string) bool {
+	for _, allowed := range r.Baggage {
+		if allowed == "*" || allowed == key {
+			return true
+		}
+	}
+	return false
+}
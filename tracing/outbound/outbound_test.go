@@ -0,0 +1,69 @@
+package outbound_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/weeb-vip/go-tracing-lib/tracing/outbound"
+)
+
+func traceContext(t *testing.T) context.Context {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	state, _ := trace.ParseTraceState("vendor=internal")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+		TraceState: state,
+	}))
+	bag, err := baggage.Parse("user.id=42,tenant=acme")
+	assert.NoError(t, err)
+	return baggage.ContextWithBaggage(ctx, bag)
+}
+
+func inject(ctx context.Context, policy *outbound.Policy, destination string) propagation.MapCarrier {
+	carrier := propagation.MapCarrier{}
+	base := propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+	policy.Propagator(destination, base).Inject(ctx, carrier)
+	return carrier
+}
+
+func TestPolicy(t *testing.T) {
+	policy := outbound.New(
+		outbound.Allow("*.svc.cluster.local", outbound.All),
+		outbound.Allow("api.partner.com", outbound.Rule{TraceParent: true, Baggage: []string{"tenant"}}),
+	)
+
+	t.Run("Should propagate the whole context to matching destinations", func(t *testing.T) {
+		a := assert.New(t)
+
+		carrier := inject(traceContext(t), policy, "users.svc.cluster.local")
+
+		a.Equal("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", carrier.Get("traceparent"))
+		a.Equal("vendor=internal", carrier.Get("tracestate"))
+		a.Contains(carrier.Get("baggage"), "user.id=42")
+	})
+
+	t.Run("Should only propagate what the rule allows", func(t *testing.T) {
+		a := assert.New(t)
+
+		carrier := inject(traceContext(t), policy, "api.partner.com")
+
+		a.NotEmpty(carrier.Get("traceparent"))
+		a.Empty(carrier.Get("tracestate"))
+		a.Equal("tenant=acme", carrier.Get("baggage"))
+	})
+
+	t.Run("Should propagate nothing to other destinations by default", func(t *testing.T) {
+		a := assert.New(t)
+
+		a.Empty(inject(traceContext(t), policy, "api.vendor.com"))
+		a.Equal(outbound.All, outbound.New(outbound.WithDefault(outbound.All)).Rule("api.vendor.com"))
+	})
+}
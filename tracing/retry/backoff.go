@@ -0,0 +1,67 @@
+package retry
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// Stop is returned by a BackOff when no more attempts should be made
+const Stop time.Duration = -1
+
+// BackOff decides how long to wait before each retry. It matches the BackOff
+// interface of github.com/cenkalti/backoff, whose policies can be used as is.
+type BackOff interface {
+	// NextBackOff returns the wait before the next attempt, or Stop
+	NextBackOff() time.Duration
+	// Reset restarts the policy before the first attempt
+	Reset()
+}
+
+// ExponentialBackOff multiplies the wait after every attempt, randomised by
+// RandomizationFactor to spread the retries of concurrent callers
+type ExponentialBackOff struct {
+	InitialInterval     time.Duration
+	MaxInterval         time.Duration
+	Multiplier          float64
+	RandomizationFactor float64
+
+	current time.Duration
+}
+
+// NewExponentialBackOff waits 100ms, then doubles the wait up to 10s, randomised by 50%
+func NewExponentialBackOff() *ExponentialBackOff {
+	b := &ExponentialBackOff{
+		InitialInterval:     100 * time.Millisecond,
+		MaxInterval:         10 * time.Second,
+		Multiplier:          2,
+		RandomizationFactor: 0.5,
+	}
+	b.Reset()
+	return b
+}
+
+func (b *ExponentialBackOff) NextBackOff() time.Duration {
+	wait := b.current
+	if next := time.Duration(float64(b.current) * b.Multiplier); next > b.MaxInterval {
+		b.current = b.MaxInterval
+	} else {
+		b.current = next
+	}
+	delta := b.RandomizationFactor * float64(wait)
+	return time.Duration(float64(wait) - delta + rand.Float64()*(2*delta))
+}
+
+func (b *ExponentialBackOff) Reset() {
+	b.current = b.InitialInterval
+}
+
+// ConstantBackOff always waits Interval
+type ConstantBackOff struct {
+	Interval time.Duration
+}
+
+func (b ConstantBackOff) NextBackOff() time.Duration {
+	return b.Interval
+}
+
+func (b ConstantBackOff) Reset() {}
@@ -0,0 +1,66 @@
+package retry
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/weeb-vip/go-tracing-lib/tracing/retry"
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	newBackOff     func() BackOff
+	maxAttempts    int
+	retryable      func(error) bool
+}
+
+// Option configures Do
+type Option func(*config)
+
+// WithTracerProvider sets the tracer provider, defaults to the global provider
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = provider
+	}
+}
+
+// WithBackOff sets the policy deciding how long to wait between attempts,
+// newBackOff is called once per Do. Defaults to NewExponentialBackOff.
+func WithBackOff(newBackOff func() BackOff) Option {
+	return func(c *config) {
+		c.newBackOff = newBackOff
+	}
+}
+
+// WithMaxAttempts caps the number of attempts, defaults to 5. With 0 the
+// attempts are only limited by the back-off policy.
+func WithMaxAttempts(attempts int) Option {
+	return func(c *config) {
+		c.maxAttempts = attempts
+	}
+}
+
+// WithRetryIf classifies errors, those for which retryable returns false end
+// the retries like errors wrapped with Permanent
+func WithRetryIf(retryable func(error) bool) Option {
+	return func(c *config) {
+		c.retryable = retryable
+	}
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{
+		tracerProvider: otel.GetTracerProvider(),
+		newBackOff:     func() BackOff { return NewExponentialBackOff() },
+		maxAttempts:    5,
+		retryable:      func(error) bool { return true },
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *config) tracer() trace.Tracer {
+	return c.tracerProvider.Tracer(instrumentationName)
+}
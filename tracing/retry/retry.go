@@ -0,0 +1,134 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// AttemptKey is the number of an attempt, starting at 1
+	AttemptKey = attribute.Key("retry.attempt")
+	// AttemptsKey is the number of attempts made
+	AttemptsKey = attribute.Key("retry.attempts")
+	// WaitKey is the time in milliseconds waited before an attempt
+	WaitKey = attribute.Key("retry.wait_ms")
+	// PermanentKey is set on failed attempts, telling whether their error ended the retries
+	PermanentKey = attribute.Key("retry.permanent")
+	// OutcomeKey is how the retries ended
+	OutcomeKey = attribute.Key("retry.outcome")
+)
+
+// Outcomes of Do
+const (
+	OutcomeSuccess   = "success"
+	OutcomePermanent = "permanent_error"
+	OutcomeExhausted = "exhausted"
+	OutcomeCanceled  = "canceled"
+)
+
+// Operation is a function retried by Do, ctx carries the span of the attempt
+type Operation func(ctx context.Context) error
+
+// PermanentError ends the retries, see Permanent
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+// Permanent wraps an error that should not be retried
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &PermanentError{Err: err}
+}
+
+// Do runs operation until it succeeds, returns a permanent error, the attempts
+// are exhausted or ctx is done. The attempts run in child spans of a span
+// named name, each recording its number and the wait that preceded it; the
+// parent span records the number of attempts and the outcome.
+//
+// It returns the error of the last attempt, unwrapped from Permanent, joined
+// with the context error when ctx was done while waiting for the next attempt.
+func Do(ctx context.Context, name string, operation Operation, opts ...Option) error {
+	c := newConfig(opts)
+	backOff := c.newBackOff()
+	backOff.Reset()
+
+	ctx, span := c.tracer().Start(ctx, name)
+	defer span.End()
+
+	var wait time.Duration
+	for attempt := 1; ; attempt++ {
+		permanent, err := c.attempt(ctx, name, attempt, wait, operation)
+		if err == nil {
+			finish(span, attempt, OutcomeSuccess, nil)
+			return nil
+		}
+		if permanent {
+			finish(span, attempt, OutcomePermanent, err)
+			return err
+		}
+
+		if wait = backOff.NextBackOff(); wait == Stop || attempt == c.maxAttempts {
+			finish(span, attempt, OutcomeExhausted, err)
+			return err
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			err = errors.Join(err, ctx.Err())
+			finish(span, attempt, OutcomeCanceled, err)
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// attempt runs operation in the span of an attempt and returns its error,
+// unwrapped from Permanent, and whether it ends the retries
+func (c *config) attempt(ctx context.Context, name string, attempt int, wait time.Duration, operation Operation) (bool, error) {
+	ctx, span := c.tracer().Start(ctx, name+" attempt", trace.WithAttributes(
+		AttemptKey.Int(attempt),
+		WaitKey.Int64(wait.Milliseconds()),
+	))
+	defer span.End()
+
+	err := operation(ctx)
+	if err == nil {
+		return false, nil
+	}
+	var permanent bool
+	var wrapped *PermanentError
+	if errors.As(err, &wrapped) {
+		err, permanent = wrapped.Err, true
+	} else {
+		permanent = !c.retryable(err)
+	}
+	span.SetAttributes(PermanentKey.Bool(permanent))
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+	return permanent, err
+}
+
+func finish(span trace.Span, attempts int, outcome string, err error) {
+	span.SetAttributes(AttemptsKey.Int(attempts), OutcomeKey.String(outcome))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
@@ -0,0 +1,138 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/weeb-vip/go-tracing-lib/tracing/retry"
+)
+
+func setup() ([]retry.Option, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	return []retry.Option{
+		retry.WithTracerProvider(trace.NewTracerProvider(trace.WithSpanProcessor(recorder))),
+		retry.WithBackOff(func() retry.BackOff { return retry.ConstantBackOff{Interval: time.Millisecond} }),
+	}, recorder
+}
+
+func TestDo(t *testing.T) {
+	t.Run("Should retry until the operation succeeds", func(t *testing.T) {
+		a := assert.New(t)
+		opts, recorder := setup()
+		failure := errors.New("unavailable")
+
+		calls := 0
+		err := retry.Do(context.Background(), "fetch user", func(ctx context.Context) error {
+			if calls++; calls < 3 {
+				return failure
+			}
+			return nil
+		}, opts...)
+
+		a.NoError(err)
+		spans := recorder.Ended()
+		a.Len(spans, 4)
+		parent := spans[3]
+		a.Equal("fetch user", parent.Name())
+		a.Contains(parent.Attributes(), attribute.Int("retry.attempts", 3))
+		a.Contains(parent.Attributes(), attribute.String("retry.outcome", "success"))
+
+		first, second := spans[0], spans[1]
+		a.Equal("fetch user attempt", first.Name())
+		a.Equal(parent.SpanContext().SpanID(), first.Parent().SpanID())
+		a.Contains(first.Attributes(), attribute.Int("retry.attempt", 1))
+		a.Contains(first.Attributes(), attribute.Int64("retry.wait_ms", 0))
+		a.Contains(first.Attributes(), attribute.Bool("retry.permanent", false))
+		a.Equal(codes.Error, first.Status().Code)
+		a.Contains(second.Attributes(), attribute.Int64("retry.wait_ms", 1))
+		a.Equal(codes.Unset, spans[2].Status().Code)
+	})
+
+	t.Run("Should stop at permanent errors", func(t *testing.T) {
+		a := assert.New(t)
+		opts, recorder := setup()
+		failure := errors.New("not found")
+
+		err := retry.Do(context.Background(), "fetch user", func(ctx context.Context) error {
+			return retry.Permanent(failure)
+		}, opts...)
+
+		a.Equal(failure, err)
+		spans := recorder.Ended()
+		a.Len(spans, 2)
+		a.Contains(spans[0].Attributes(), attribute.Bool("retry.permanent", true))
+		a.Contains(spans[1].Attributes(), attribute.String("retry.outcome", "permanent_error"))
+	})
+
+	t.Run("Should classify errors with WithRetryIf", func(t *testing.T) {
+		a := assert.New(t)
+		opts, recorder := setup()
+		failure := errors.New("bad request")
+
+		err := retry.Do(context.Background(), "fetch user", func(ctx context.Context) error {
+			return failure
+		}, append(opts, retry.WithRetryIf(func(err error) bool { return !errors.Is(err, failure) }))...)
+
+		a.ErrorIs(err, failure)
+		a.Len(recorder.Ended(), 2)
+	})
+
+	t.Run("Should give up after the maximum attempts", func(t *testing.T) {
+		a := assert.New(t)
+		opts, recorder := setup()
+		failure := errors.New("unavailable")
+
+		err := retry.Do(context.Background(), "fetch user", func(ctx context.Context) error {
+			return failure
+		}, append(opts, retry.WithMaxAttempts(3))...)
+
+		a.ErrorIs(err, failure)
+		spans := recorder.Ended()
+		a.Len(spans, 4)
+		a.Contains(spans[3].Attributes(), attribute.Int("retry.attempts", 3))
+		a.Contains(spans[3].Attributes(), attribute.String("retry.outcome", "exhausted"))
+		a.Equal(codes.Error, spans[3].Status().Code)
+	})
+
+	t.Run("Should stop waiting when the context is done", func(t *testing.T) {
+		a := assert.New(t)
+		opts, recorder := setup()
+		failure := errors.New("unavailable")
+		ctx, cancel := context.WithCancel(context.Background())
+
+		err := retry.Do(ctx, "fetch user", func(ctx context.Context) error {
+			cancel()
+			return failure
+		}, append(opts, retry.WithBackOff(func() retry.BackOff { return retry.ConstantBackOff{Interval: time.Hour} }))...)
+
+		a.ErrorIs(err, failure)
+		a.ErrorIs(err, context.Canceled)
+		spans := recorder.Ended()
+		a.Len(spans, 2)
+		a.Contains(spans[1].Attributes(), attribute.String("retry.outcome", "canceled"))
+	})
+}
+
+func TestExponentialBackOff(t *testing.T) {
+	t.Run("Should double the wait up to the maximum", func(t *testing.T) {
+		a := assert.New(t)
+		backOff := &retry.ExponentialBackOff{InitialInterval: time.Second, MaxInterval: 3 * time.Second, Multiplier: 2}
+
+		backOff.Reset()
+		a.Equal(time.Second, backOff.NextBackOff())
+		a.Equal(2*time.Second, backOff.NextBackOff())
+		a.Equal(3*time.Second, backOff.NextBackOff())
+		a.Equal(3*time.Second, backOff.NextBackOff())
+
+		backOff.Reset()
+		a.Equal(time.Second, backOff.NextBackOff())
+	})
+}
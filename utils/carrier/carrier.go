@@ -0,0 +1,154 @@
+package carrier
+
+import (
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// KeyFunc normalises header keys, e.g. strings.ToLower or textproto.CanonicalMIMEHeaderKey
+type KeyFunc func(key string) string
+
+// Option configures a carrier
+type Option func(*options)
+
+type options struct {
+	key KeyFunc
+}
+
+// WithKeyFunc normalises the keys a carrier sets and looks up. Headers already
+// present are found whatever their spelling as long as they normalise to the same key.
+func WithKeyFunc(key KeyFunc) Option {
+	return func(o *options) {
+		o.key = key
+	}
+}
+
+// Map is a carrier over a header map whose values are of type V
+type Map[V any] struct {
+	headers map[string]V
+	key     KeyFunc
+	encode  func(string) V
+	decode  func(V) (string, bool)
+}
+
+var (
+	_ propagation.TextMapCarrier = &Map[string]{}
+	_ propagation.TextMapCarrier = &Map[any]{}
+	_ propagation.TextMapCarrier = &Map[[]string]{}
+	_ propagation.TextMapCarrier = &Map[[]byte]{}
+)
+
+func newMap[V any](headers map[string]V, opts []Option, encode func(string) V, decode func(V) (string, bool)) *Map[V] {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if headers == nil {
+		headers = make(map[string]V)
+	}
+	return &Map[V]{headers: headers, key: o.key, encode: encode, decode: decode}
+}
+
+// NewStringMap creates a carrier over map[string]string headers, a nil map is allocated
+func NewStringMap(headers map[string]string, opts ...Option) *Map[string] {
+	return newMap(headers, opts,
+		func(value string) string { return value },
+		func(value string) (string, bool) { return value, true },
+	)
+}
+
+// NewAnyMap creates a carrier over map[string]any headers such as amqp.Table.
+// Values are set as strings and read from string and []byte values, a nil map is allocated.
+func NewAnyMap(headers map[string]any, opts ...Option) *Map[any] {
+	return newMap(headers, opts,
+		func(value string) any { return value },
+		func(value any) (string, bool) {
+			switch v := value.(type) {
+			case string:
+				return v, true
+			case []byte:
+				return string(v), true
+			default:
+				return "", false
+			}
+		},
+	)
+}
+
+// NewMultiMap creates a carrier over multi-value map[string][]string headers
+// such as http.Header or nats.Header. The first value of a key is read and
+// setting a key replaces all its values, a nil map is allocated.
+func NewMultiMap(headers map[string][]string, opts ...Option) *Map[[]string] {
+	return newMap(headers, opts,
+		func(value string) []string { return []string{value} },
+		func(value []string) (string, bool) {
+			if len(value) == 0 {
+				return "", false
+			}
+			return value[0], true
+		},
+	)
+}
+
+// NewBytesMap creates a carrier over map[string][]byte headers, a nil map is allocated
+func NewBytesMap(headers map[string][]byte, opts ...Option) *Map[[]byte] {
+	return newMap(headers, opts,
+		func(value string) []byte { return []byte(value) },
+		func(value []byte) (string, bool) { return string(value), true },
+	)
+}
+
+// Headers returns the carried map
+func (m *Map[V]) Headers() map[string]V {
+	return m.headers
+}
+
+func (m *Map[V]) Get(key string) string {
+	if value, ok := m.lookup(key); ok {
+		if s, ok := m.decode(value); ok {
+			return s
+		}
+	}
+	return ""
+}
+
+func (m *Map[V]) Set(key string, value string) {
+	if m.key != nil {
+		key = m.key(key)
+		// drop differently spelled duplicates of the key
+		for k := range m.headers {
+			if k != key && m.key(k) == key {
+				delete(m.headers, k)
+			}
+		}
+	}
+	m.headers[key] = m.encode(value)
+}
+
+func (m *Map[V]) Keys() []string {
+	keys := make([]string, 0, len(m.headers))
+	for k := range m.headers {
+		if m.key != nil {
+			k = m.key(k)
+		}
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func (m *Map[V]) lookup(key string) (V, bool) {
+	if m.key == nil {
+		value, ok := m.headers[key]
+		return value, ok
+	}
+	key = m.key(key)
+	if value, ok := m.headers[key]; ok {
+		return value, true
+	}
+	for k, value := range m.headers {
+		if m.key(k) == key {
+			return value, true
+		}
+	}
+	var zero V
+	return zero, false
+}
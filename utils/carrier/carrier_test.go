@@ -0,0 +1,68 @@
+package carrier_test
+
+import (
+	"context"
+	"net/http"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/weeb-vip/go-tracing-lib/utils/carrier"
+)
+
+func spanContext() context.Context {
+	return trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{2},
+		TraceFlags: trace.FlagsSampled,
+	}))
+}
+
+func TestCarriers(t *testing.T) {
+	propagator := propagation.TraceContext{}
+
+	t.Run("Should round trip the trace context through every header type", func(t *testing.T) {
+		a := assert.New(t)
+		carriers := []propagation.TextMapCarrier{
+			carrier.NewStringMap(nil),
+			carrier.NewAnyMap(nil),
+			carrier.NewMultiMap(nil),
+			carrier.NewBytesMap(nil),
+		}
+
+		for _, c := range carriers {
+			propagator.Inject(spanContext(), c)
+			extracted := trace.SpanContextFromContext(propagator.Extract(context.Background(), c))
+			a.Equal(trace.SpanID{2}, extracted.SpanID())
+			a.Equal([]string{"traceparent"}, c.Keys())
+		}
+	})
+
+	t.Run("Should read []byte values of any maps", func(t *testing.T) {
+		a := assert.New(t)
+		c := carrier.NewAnyMap(map[string]any{"traceparent": []byte("value"), "retries": 3})
+
+		a.Equal("value", c.Get("traceparent"))
+		a.Empty(c.Get("retries"))
+	})
+
+	t.Run("Should normalise keys", func(t *testing.T) {
+		a := assert.New(t)
+		headers := http.Header{}
+		headers["traceparent"] = []string{"old"}
+		c := carrier.NewMultiMap(headers, carrier.WithKeyFunc(textproto.CanonicalMIMEHeaderKey))
+
+		a.Equal("old", c.Get("Traceparent"))
+		c.Set("traceparent", "new")
+		a.Equal("new", headers.Get("Traceparent"))
+		a.Len(headers, 1)
+
+		lower := carrier.NewStringMap(map[string]string{"TraceParent": "value"}, carrier.WithKeyFunc(strings.ToLower))
+		a.Equal("value", lower.Get("traceparent"))
+		a.Equal([]string{"traceparent"}, lower.Keys())
+	})
+}
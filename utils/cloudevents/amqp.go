@@ -0,0 +1,122 @@
+package cloudevents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/types"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const (
+	// AMQPHeaderPrefix prefixes the event attributes in the headers of binary mode messages
+	AMQPHeaderPrefix = "cloudEvents_"
+
+	structuredContentType = event.ApplicationCloudEventsJSON
+)
+
+// NewPublishing creates a RabbitMQ message carrying e in mode, with the trace
+// context of ctx in its distributed tracing extension. Publish it with the
+// traced rabbitmq.Publisher to also propagate the trace context in the message headers.
+func NewPublishing(ctx context.Context, e event.Event, mode Mode, opts ...Option) (amqp.Publishing, error) {
+	e = e.Clone()
+	InjectTraceContext(ctx, &e, opts...)
+	if err := e.Validate(); err != nil {
+		return amqp.Publishing{}, fmt.Errorf("invalid event: %w", err)
+	}
+
+	if mode == ModeStructured {
+		body, err := json.Marshal(e)
+		if err != nil {
+			return amqp.Publishing{}, fmt.Errorf("failed to marshal event: %w", err)
+		}
+		return amqp.Publishing{ContentType: structuredContentType, Body: body}, nil
+	}
+
+	headers := amqp.Table{
+		AMQPHeaderPrefix + "specversion": e.SpecVersion(),
+		AMQPHeaderPrefix + "id":          e.ID(),
+		AMQPHeaderPrefix + "source":      e.Source(),
+		AMQPHeaderPrefix + "type":        e.Type(),
+	}
+	if e.Subject() != "" {
+		headers[AMQPHeaderPrefix+"subject"] = e.Subject()
+	}
+	if e.DataSchema() != "" {
+		headers[AMQPHeaderPrefix+"dataschema"] = e.DataSchema()
+	}
+	if !e.Time().IsZero() {
+		headers[AMQPHeaderPrefix+"time"] = e.Time().Format(time.RFC3339Nano)
+	}
+	for name, value := range e.Extensions() {
+		s, err := types.Format(value)
+		if err != nil {
+			return amqp.Publishing{}, fmt.Errorf("invalid extension %s: %w", name, err)
+		}
+		headers[AMQPHeaderPrefix+name] = s
+	}
+	return amqp.Publishing{Headers: headers, ContentType: e.DataContentType(), Body: e.Data()}, nil
+}
+
+// EventFromDelivery reads the event of a binary or structured mode delivery.
+// Use ExtractTraceContext to continue its trace.
+func EventFromDelivery(delivery amqp.Delivery) (*event.Event, error) {
+	e := event.New()
+	if strings.HasPrefix(delivery.ContentType, structuredContentType) {
+		if err := json.Unmarshal(delivery.Body, &e); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal event: %w", err)
+		}
+		return &e, nil
+	}
+
+	for key, value := range delivery.Headers {
+		name, ok := strings.CutPrefix(key, AMQPHeaderPrefix)
+		if !ok {
+			continue
+		}
+		var s string
+		switch v := value.(type) {
+		case string:
+			s = v
+		case []byte:
+			s = string(v)
+		default:
+			continue
+		}
+
+		switch name {
+		case "specversion":
+			e.SetSpecVersion(s)
+		case "id":
+			e.SetID(s)
+		case "source":
+			e.SetSource(s)
+		case "type":
+			e.SetType(s)
+		case "subject":
+			e.SetSubject(s)
+		case "dataschema":
+			e.SetDataSchema(s)
+		case "time":
+			t, err := types.ParseTime(s)
+			if err != nil {
+				return nil, fmt.Errorf("invalid event time: %w", err)
+			}
+			e.SetTime(t)
+		default:
+			e.SetExtension(name, s)
+		}
+	}
+	if delivery.ContentType != "" {
+		e.SetDataContentType(delivery.ContentType)
+	}
+	e.DataEncoded = delivery.Body
+	if err := e.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid event: %w", err)
+	}
+	return &e, nil
+}
@@ -0,0 +1,101 @@
+package cloudevents
+
+import (
+	"context"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/extensions"
+	"github.com/cloudevents/sdk-go/v2/types"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// Mode is the content mode events are written in
+type Mode int
+
+const (
+	// ModeBinary writes the event attributes as transport headers and the data as body
+	ModeBinary Mode = iota
+	// ModeStructured writes the whole event as a JSON body
+	ModeStructured
+)
+
+// ExtensionCarrier adapts the distributed tracing extension of an event to a
+// propagation.TextMapCarrier. Only the traceparent and tracestate attributes
+// defined by the extension are carried.
+type ExtensionCarrier struct {
+	event *event.Event
+}
+
+var _ propagation.TextMapCarrier = &ExtensionCarrier{}
+
+func NewExtensionCarrier(e *event.Event) *ExtensionCarrier {
+	return &ExtensionCarrier{event: e}
+}
+
+func (c *ExtensionCarrier) Get(key string) string {
+	if !isTracingExtension(key) {
+		return ""
+	}
+	value, ok := c.event.Extensions()[key]
+	if !ok {
+		return ""
+	}
+	s, err := types.ToString(value)
+	if err != nil {
+		return ""
+	}
+	return s
+}
+
+func (c *ExtensionCarrier) Set(key string, value string) {
+	if isTracingExtension(key) {
+		c.event.SetExtension(key, value)
+	}
+}
+
+func (c *ExtensionCarrier) Keys() []string {
+	var keys []string
+	for key := range c.event.Extensions() {
+		if isTracingExtension(key) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func isTracingExtension(key string) bool {
+	return key == extensions.TraceParentExtension || key == extensions.TraceStateExtension
+}
+
+// Option configures the propagation helpers
+type Option func(*config)
+
+type config struct {
+	propagator propagation.TextMapPropagator
+}
+
+// WithPropagator sets the propagator, defaults to the global propagator
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagator = propagator
+	}
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{propagator: otel.GetTextMapPropagator()}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// InjectTraceContext sets the distributed tracing extension of e to the trace context of ctx
+func InjectTraceContext(ctx context.Context, e *event.Event, opts ...Option) {
+	newConfig(opts).propagator.Inject(ctx, NewExtensionCarrier(e))
+}
+
+// ExtractTraceContext returns ctx with the trace context of the distributed tracing extension of e
+func ExtractTraceContext(ctx context.Context, e event.Event, opts ...Option) context.Context {
+	return newConfig(opts).propagator.Extract(ctx, NewExtensionCarrier(&e))
+}
@@ -0,0 +1,128 @@
+package cloudevents_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/weeb-vip/go-tracing-lib/utils/cloudevents"
+)
+
+// the examples of the distributed tracing extension and W3C Trace Context specifications
+const (
+	traceParent = "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+	traceState  = "rojo=00f067aa0ba902b7,congo=t61rcWkgMzE"
+)
+
+var opts = []cloudevents.Option{cloudevents.WithPropagator(propagation.TraceContext{})}
+
+func newEvent() event.Event {
+	e := event.New()
+	e.SetID("A234-1234-1234")
+	e.SetSource("https://github.com/cloudevents/spec/pull")
+	e.SetType("com.github.pull_request.opened")
+	e.SetSubject("123")
+	_ = e.SetData(event.ApplicationJSON, map[string]string{"name": "alice"})
+	return e
+}
+
+func tracedContext() context.Context {
+	e := newEvent()
+	e.SetExtension("traceparent", traceParent)
+	e.SetExtension("tracestate", traceState)
+	return cloudevents.ExtractTraceContext(context.Background(), e, opts...)
+}
+
+func assertTraceExtension(a *assert.Assertions, e event.Event) {
+	a.Equal(traceParent, e.Extensions()["traceparent"])
+	a.Equal(traceState, e.Extensions()["tracestate"])
+}
+
+func TestTraceContext(t *testing.T) {
+	t.Run("Should extract the extension attributes of the spec example", func(t *testing.T) {
+		a := assert.New(t)
+
+		spanContext := trace.SpanContextFromContext(tracedContext())
+
+		a.Equal("0af7651916cd43dd8448eb211c80319c", spanContext.TraceID().String())
+		a.Equal("b7ad6b7169203331", spanContext.SpanID().String())
+		a.True(spanContext.IsSampled())
+		a.Equal(traceState, spanContext.TraceState().String())
+	})
+
+	t.Run("Should inject only the extension attributes", func(t *testing.T) {
+		a := assert.New(t)
+		e := newEvent()
+
+		propagator := propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+		cloudevents.InjectTraceContext(tracedContext(), &e, cloudevents.WithPropagator(propagator))
+
+		assertTraceExtension(a, e)
+		a.Len(e.Extensions(), 2)
+	})
+}
+
+func TestHTTP(t *testing.T) {
+	t.Run("Should carry the extension in ce- headers in binary mode", func(t *testing.T) {
+		a := assert.New(t)
+
+		req, err := cloudevents.NewHTTPRequest(tracedContext(), http.MethodPost, "http://localhost/events", newEvent(), cloudevents.ModeBinary, opts...)
+		a.NoError(err)
+
+		a.Equal(traceParent, req.Header.Get("ce-traceparent"))
+		a.Equal(traceState, req.Header.Get("ce-tracestate"))
+		a.Equal("application/json", req.Header.Get("Content-Type"))
+
+		e, err := cloudevents.EventFromHTTPRequest(req)
+		a.NoError(err)
+		assertTraceExtension(a, *e)
+		a.Equal("A234-1234-1234", e.ID())
+	})
+
+	t.Run("Should carry the extension in the JSON body in structured mode", func(t *testing.T) {
+		a := assert.New(t)
+
+		req, err := cloudevents.NewHTTPRequest(tracedContext(), http.MethodPost, "http://localhost/events", newEvent(), cloudevents.ModeStructured, opts...)
+		a.NoError(err)
+		a.Equal("application/cloudevents+json", req.Header.Get("Content-Type"))
+		a.Empty(req.Header.Get("ce-traceparent"))
+
+		body, err := io.ReadAll(req.Body)
+		a.NoError(err)
+		var document map[string]any
+		a.NoError(json.Unmarshal(body, &document))
+		a.Equal(traceParent, document["traceparent"])
+		a.Equal(traceState, document["tracestate"])
+	})
+}
+
+func TestAMQP(t *testing.T) {
+	for _, mode := range []cloudevents.Mode{cloudevents.ModeBinary, cloudevents.ModeStructured} {
+		t.Run("Should round trip the extension through RabbitMQ messages", func(t *testing.T) {
+			a := assert.New(t)
+
+			msg, err := cloudevents.NewPublishing(tracedContext(), newEvent(), mode, opts...)
+			a.NoError(err)
+			if mode == cloudevents.ModeBinary {
+				a.Equal(traceParent, msg.Headers["cloudEvents_traceparent"])
+				a.Equal("application/json", msg.ContentType)
+			} else {
+				a.Equal("application/cloudevents+json", msg.ContentType)
+			}
+
+			e, err := cloudevents.EventFromDelivery(amqp.Delivery{Headers: msg.Headers, ContentType: msg.ContentType, Body: msg.Body})
+			a.NoError(err)
+			assertTraceExtension(a, *e)
+			a.Equal("123", e.Subject())
+			a.JSONEq(`{"name":"alice"}`, string(e.Data()))
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package cloudevents
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+)
+
+// NewHTTPRequest creates a request carrying e in mode, with the trace context
+// of ctx in its distributed tracing extension. Send it with the traced
+// http_client to also propagate the trace context in the HTTP headers.
+func NewHTTPRequest(ctx context.Context, method, url string, e event.Event, mode Mode, opts ...Option) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	e = e.Clone()
+	InjectTraceContext(ctx, &e, opts...)
+	if err := cehttp.WriteRequest(withMode(ctx, mode), binding.ToMessage(&e), req); err != nil {
+		return nil, fmt.Errorf("failed to write event: %w", err)
+	}
+	return req, nil
+}
+
+// EventFromHTTPRequest reads the event of a binary or structured request.
+// Use ExtractTraceContext to continue its trace.
+func EventFromHTTPRequest(req *http.Request) (*event.Event, error) {
+	message := cehttp.NewMessageFromHttpRequest(req)
+	defer message.Finish(nil)
+
+	e, err := binding.ToEvent(req.Context(), message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read event: %w", err)
+	}
+	return e, nil
+}
+
+func withMode(ctx context.Context, mode Mode) context.Context {
+	if mode == ModeStructured {
+		return binding.WithForceStructured(ctx)
+	}
+	return binding.WithForceBinary(ctx)
+}
@@ -0,0 +1,144 @@
+package envelope
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+// Codec encodes envelopes to and from bytes
+type Codec[T any] interface {
+	Marshal(e *Envelope[T]) ([]byte, error)
+	Unmarshal(data []byte, e *Envelope[T]) error
+}
+
+// JSONCodec encodes envelopes as JSON
+type JSONCodec[T any] struct{}
+
+func (JSONCodec[T]) Marshal(e *Envelope[T]) ([]byte, error) {
+	return json.Marshal(e)
+}
+
+func (JSONCodec[T]) Unmarshal(data []byte, e *Envelope[T]) error {
+	return json.Unmarshal(data, e)
+}
+
+// ProtoCodec encodes envelopes of protobuf payloads in the protobuf wire format of
+//
+//	message Envelope {
+//	  string key = 1;
+//	  string traceparent = 2;
+//	  string tracestate = 3;
+//	  string baggage = 4;
+//	  int64 enqueued_at_unix_nano = 5;
+//	  int64 retries = 6;
+//	  bytes payload = 7;
+//	}
+type ProtoCodec[T proto.Message] struct{}
+
+const (
+	protoKey protowire.Number = iota + 1
+	protoTraceParent
+	protoTraceState
+	protoBaggage
+	protoEnqueuedAt
+	protoRetries
+	protoPayload
+)
+
+func (ProtoCodec[T]) Marshal(e *Envelope[T]) ([]byte, error) {
+	payload, err := proto.Marshal(e.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	var b []byte
+	for _, field := range []struct {
+		number protowire.Number
+		value  string
+	}{
+		{protoKey, e.Header.Key},
+		{protoTraceParent, e.Header.TraceParent},
+		{protoTraceState, e.Header.TraceState},
+		{protoBaggage, e.Header.Baggage},
+	} {
+		if field.value != "" {
+			b = protowire.AppendTag(b, field.number, protowire.BytesType)
+			b = protowire.AppendString(b, field.value)
+		}
+	}
+	if !e.Header.EnqueuedAt.IsZero() {
+		b = protowire.AppendTag(b, protoEnqueuedAt, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(e.Header.EnqueuedAt.UnixNano()))
+	}
+	if e.Retries != 0 {
+		b = protowire.AppendTag(b, protoRetries, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(e.Retries))
+	}
+	b = protowire.AppendTag(b, protoPayload, protowire.BytesType)
+	b = protowire.AppendBytes(b, payload)
+	return b, nil
+}
+
+func (ProtoCodec[T]) Unmarshal(data []byte, e *Envelope[T]) error {
+	var zero T
+	payload := zero.ProtoReflect().Type().New().Interface().(T)
+	*e = Envelope[T]{Payload: payload}
+
+	for len(data) > 0 {
+		number, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return fmt.Errorf("failed to unmarshal envelope: %w", protowire.ParseError(n))
+		}
+		data = data[n:]
+
+		switch {
+		case typ == protowire.BytesType && number >= protoKey && number <= protoBaggage:
+			value, n := protowire.ConsumeString(data)
+			if n < 0 {
+				return fmt.Errorf("failed to unmarshal envelope: %w", protowire.ParseError(n))
+			}
+			data = data[n:]
+			switch number {
+			case protoKey:
+				e.Header.Key = value
+			case protoTraceParent:
+				e.Header.TraceParent = value
+			case protoTraceState:
+				e.Header.TraceState = value
+			case protoBaggage:
+				e.Header.Baggage = value
+			}
+		case typ == protowire.VarintType && (number == protoEnqueuedAt || number == protoRetries):
+			value, n := protowire.ConsumeVarint(data)
+			if n < 0 {
+				return fmt.Errorf("failed to unmarshal envelope: %w", protowire.ParseError(n))
+			}
+			data = data[n:]
+			if number == protoEnqueuedAt {
+				e.Header.EnqueuedAt = time.Unix(0, int64(value))
+			} else {
+				e.Retries = int(value)
+			}
+		case typ == protowire.BytesType && number == protoPayload:
+			value, n := protowire.ConsumeBytes(data)
+			if n < 0 {
+				return fmt.Errorf("failed to unmarshal envelope: %w", protowire.ParseError(n))
+			}
+			data = data[n:]
+			if err := proto.Unmarshal(value, payload); err != nil {
+				return fmt.Errorf("failed to unmarshal payload: %w", err)
+			}
+		default:
+			n := protowire.ConsumeFieldValue(number, typ, data)
+			if n < 0 {
+				return fmt.Errorf("failed to unmarshal envelope: %w", protowire.ParseError(n))
+			}
+			data = data[n:]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package envelope
+
+import (
+	"time"
+)
+
+const (
+	traceParentHeader = "traceparent"
+	traceStateHeader  = "tracestate"
+	baggageHeader     = "baggage"
+)
+
+// Header carries the trace context of an envelope alongside its payload
+type Header struct {
+	Key         string    `json:"key,omitempty"`
+	TraceParent string    `json:"traceparent,omitempty"`
+	TraceState  string    `json:"tracestate,omitempty"`
+	Baggage     string    `json:"baggage,omitempty"`
+	EnqueuedAt  time.Time `json:"enqueued_at"`
+}
+
+// Envelope wraps a payload with its trace context for transports without
+// headers, such as Redis Pub/Sub, plain TCP or webhooks. It implements
+// redis.RedisMessage.
+type Envelope[T any] struct {
+	Header  Header `json:"header"`
+	Payload T      `json:"payload"`
+	Retries int    `json:"retries"`
+}
+
+// New creates an envelope for payload, see Wrap to fill in the trace context
+func New[T any](payload T) *Envelope[T] {
+	return &Envelope[T]{Payload: payload}
+}
+
+// Headers returns the trace context fields as a header map
+func (e *Envelope[T]) Headers() map[string]string {
+	headers := make(map[string]string, 3)
+	if e.Header.TraceParent != "" {
+		headers[traceParentHeader] = e.Header.TraceParent
+	}
+	if e.Header.TraceState != "" {
+		headers[traceStateHeader] = e.Header.TraceState
+	}
+	if e.Header.Baggage != "" {
+		headers[baggageHeader] = e.Header.Baggage
+	}
+	return headers
+}
+
+// SetHeaders sets the trace context fields from a header map
+func (e *Envelope[T]) SetHeaders(headers map[string]string) {
+	e.Header.TraceParent = headers[traceParentHeader]
+	e.Header.TraceState = headers[traceStateHeader]
+	e.Header.Baggage = headers[baggageHeader]
+}
+
+func (e *Envelope[T]) GetMsg() Envelope[T] {
+	return *e
+}
+
+// Retry returns a copy of the envelope for redelivery, with Retries incremented
+func (e *Envelope[T]) Retry() *Envelope[T] {
+	retry := *e
+	retry.Retries++
+	return &retry
+}
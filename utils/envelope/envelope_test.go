@@ -0,0 +1,123 @@
+package envelope_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/weeb-vip/go-tracing-lib/utils/envelope"
+	"github.com/weeb-vip/go-tracing-lib/utils/redis"
+)
+
+type user struct {
+	Name string `json:"name"`
+}
+
+var _ redis.RedisMessage[envelope.Envelope[user]] = &envelope.Envelope[user]{}
+
+func setup() ([]envelope.Option, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	return []envelope.Option{
+		envelope.WithTracerProvider(trace.NewTracerProvider(trace.WithSpanProcessor(recorder))),
+		envelope.WithPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})),
+		envelope.WithSystem("redis"),
+	}, recorder
+}
+
+func TestCodecs(t *testing.T) {
+	t.Run("Should round trip envelopes through JSON", func(t *testing.T) {
+		a := assert.New(t)
+		e := envelope.New(user{Name: "alice"})
+		e.Header.TraceParent = "00-01000000000000000000000000000000-0200000000000000-01"
+		e.Retries = 2
+
+		data, err := envelope.JSONCodec[user]{}.Marshal(e)
+		a.NoError(err)
+		var decoded envelope.Envelope[user]
+		a.NoError(envelope.JSONCodec[user]{}.Unmarshal(data, &decoded))
+
+		a.Equal(*e, decoded)
+	})
+
+	t.Run("Should round trip envelopes through protobuf", func(t *testing.T) {
+		a := assert.New(t)
+		e := envelope.New(wrapperspb.String("alice"))
+		e.Header = envelope.Header{
+			Key:         "user-1",
+			TraceParent: "00-01000000000000000000000000000000-0200000000000000-01",
+			TraceState:  "vendor=value",
+			Baggage:     "tenant=weeb",
+			EnqueuedAt:  time.Unix(0, 1700000000123456789),
+		}
+		e.Retries = 3
+
+		codec := envelope.ProtoCodec[*wrapperspb.StringValue]{}
+		data, err := codec.Marshal(e)
+		a.NoError(err)
+		var decoded envelope.Envelope[*wrapperspb.StringValue]
+		a.NoError(codec.Unmarshal(data, &decoded))
+
+		a.Equal(e.Header, decoded.Header)
+		a.Equal(3, decoded.Retries)
+		a.Equal("alice", decoded.Payload.GetValue())
+	})
+}
+
+func TestSendReceive(t *testing.T) {
+	t.Run("Should continue the sender's trace in the receiver", func(t *testing.T) {
+		a := assert.New(t)
+		opts, recorder := setup()
+		codec := envelope.JSONCodec[user]{}
+
+		var sent []byte
+		err := envelope.Send(context.Background(), "users", user{Name: "alice"}, codec, func(ctx context.Context, data []byte) error {
+			sent = data
+			return nil
+		}, append(opts, envelope.WithKey("user-1"))...)
+		a.NoError(err)
+
+		var received *envelope.Envelope[user]
+		err = envelope.Receive(context.Background(), "users", sent, codec, func(ctx context.Context, e *envelope.Envelope[user]) error {
+			received = e
+			return errors.New("boom")
+		}, opts...)
+		a.EqualError(err, "boom")
+
+		a.Equal("alice", received.Payload.Name)
+		a.WithinDuration(time.Now(), received.Header.EnqueuedAt, time.Minute)
+		producer, consumer := recorder.Ended()[0], recorder.Ended()[1]
+		a.Equal("users publish", producer.Name())
+		a.Equal(oteltrace.SpanKindProducer, producer.SpanKind())
+		a.Contains(producer.Attributes(), attribute.String("messaging.system", "redis"))
+		a.Equal("users process", consumer.Name())
+		a.Equal(producer.SpanContext().SpanID(), consumer.Parent().SpanID())
+		a.Contains(consumer.Attributes(), attribute.String("messaging.envelope.key", "user-1"))
+		a.Contains(consumer.Attributes(), attribute.Int("messaging.envelope.retries", 0))
+		a.Equal(codes.Error, consumer.Status().Code)
+	})
+
+	t.Run("Should link retried envelopes to the producer span", func(t *testing.T) {
+		a := assert.New(t)
+		opts, recorder := setup()
+
+		e, span := envelope.Wrap(context.Background(), "users", user{Name: "alice"}, opts...)
+		span.End()
+		_, consumer := envelope.Unwrap(context.Background(), "users", e.Retry(), append(opts, envelope.WithLinkToProducer())...)
+		consumer.End()
+
+		processed := recorder.Ended()[1]
+		a.False(processed.Parent().IsValid())
+		a.Equal(span.SpanContext().SpanID(), processed.Links()[0].SpanContext.SpanID())
+		a.Contains(processed.Attributes(), attribute.Int("messaging.envelope.retries", 1))
+	})
+}
@@ -0,0 +1,116 @@
+package envelope
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/weeb-vip/go-tracing-lib/logger"
+)
+
+const (
+	KeyKey     = attribute.Key("messaging.envelope.key")
+	RetriesKey = attribute.Key("messaging.envelope.retries")
+)
+
+// Wrap starts a producer span for sending payload to destination and returns
+// an envelope carrying it, stamped with the enqueue time. End the span once
+// the envelope is sent.
+func Wrap[T any](ctx context.Context, destination string, payload T, opts ...Option) (*Envelope[T], trace.Span) {
+	c := newConfig(opts)
+	ctx, span := c.tracer().Start(ctx, destination+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(append(c.attributes(),
+			semconv.MessagingOperationPublish,
+			semconv.MessagingDestinationName(destination),
+		)...),
+	)
+
+	e := New(payload)
+	e.Header.Key = c.key
+	e.Header.EnqueuedAt = time.Now()
+	headers := propagation.MapCarrier{}
+	c.propagator.Inject(ctx, headers)
+	e.SetHeaders(headers)
+	return e, span
+}
+
+// Unwrap starts a consumer span for processing e received from source,
+// parented to, or linked with, the producer's span. The context carries the
+// trace-aware logger. End the span once e is processed.
+func Unwrap[T any](ctx context.Context, source string, e *Envelope[T], opts ...Option) (context.Context, trace.Span) {
+	c := newConfig(opts)
+	attrs := append(c.attributes(),
+		semconv.MessagingOperationProcess,
+		semconv.MessagingSourceName(source),
+		RetriesKey.Int(e.Retries),
+	)
+	if e.Header.Key != "" {
+		attrs = append(attrs, KeyKey.String(e.Header.Key))
+	}
+	startOpts := []trace.SpanStartOption{trace.WithSpanKind(trace.SpanKindConsumer), trace.WithAttributes(attrs...)}
+
+	producerCtx := c.propagator.Extract(ctx, propagation.MapCarrier(e.Headers()))
+	if c.linkToProducer {
+		if producer := trace.SpanContextFromContext(producerCtx); producer.IsValid() {
+			startOpts = append(startOpts, trace.WithLinks(trace.Link{SpanContext: producer}))
+		}
+	} else {
+		ctx = producerCtx
+	}
+
+	ctx, span := c.tracer().Start(ctx, source+" process", startOpts...)
+	return logger.ContextWithTraceFormats(ctx, c.logFormats...), span
+}
+
+// Send wraps payload, encodes the envelope with codec and passes it to send within the producer span
+func Send[T any](ctx context.Context, destination string, payload T, codec Codec[T], send func(ctx context.Context, data []byte) error, opts ...Option) error {
+	e, span := Wrap(ctx, destination, payload, opts...)
+	defer span.End()
+
+	data, err := codec.Marshal(e)
+	if err != nil {
+		return fail(span, fmt.Errorf("failed to marshal envelope: %w", err))
+	}
+	span.SetAttributes(semconv.MessagingMessagePayloadSizeBytes(len(data)))
+	if err := send(trace.ContextWithSpan(ctx, span), data); err != nil {
+		return fail(span, err)
+	}
+	return nil
+}
+
+// Receive decodes data with codec and runs handler within the consumer span of the envelope
+func Receive[T any](ctx context.Context, source string, data []byte, codec Codec[T], handler func(ctx context.Context, e *Envelope[T]) error, opts ...Option) error {
+	var e Envelope[T]
+	if err := codec.Unmarshal(data, &e); err != nil {
+		return fmt.Errorf("failed to unmarshal envelope: %w", err)
+	}
+
+	ctx, span := Unwrap(ctx, source, &e, opts...)
+	defer span.End()
+	span.SetAttributes(semconv.MessagingMessagePayloadSizeBytes(len(data)))
+
+	if err := handler(ctx, &e); err != nil {
+		return fail(span, err)
+	}
+	return nil
+}
+
+func (c *config) attributes() []attribute.KeyValue {
+	if c.system == "" {
+		return nil
+	}
+	return []attribute.KeyValue{semconv.MessagingSystem(c.system)}
+}
+
+func fail(span trace.Span, err error) error {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+	return err
+}
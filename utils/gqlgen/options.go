@@ -0,0 +1,139 @@
+package gqlgen
+
+import (
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/weeb-vip/go-tracing-lib/utils/gqlgen"
+
+// DefaultRedactedVariables are redacted wherever they appear in a variable name
+var DefaultRedactedVariables = []string{"password", "secret", "token", "authorization", "apikey", "api_key"}
+
+type config struct {
+	tracerProvider    trace.TracerProvider
+	document          bool
+	variables         bool
+	maxVariableLength int
+	redactedNames     []string
+	fieldSpans        bool
+	maxFieldDepth     int
+	fieldFilter       func(fc *graphql.FieldContext) bool
+}
+
+// Option configures the tracer
+type Option func(*config)
+
+// WithTracerProvider sets the tracer provider, defaults to the global provider
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = provider
+	}
+}
+
+// WithDocument records the query document on the operation span
+func WithDocument() Option {
+	return func(c *config) {
+		c.document = true
+	}
+}
+
+// WithVariables records the operation variables as graphql.variables.<name>
+// attributes, redacting sensitive ones, see WithRedactedVariables. At most 32
+// variables are recorded, their values cut to WithMaxVariableLength.
+func WithVariables() Option {
+	return func(c *config) {
+		c.variables = true
+	}
+}
+
+// WithoutVariables does not record the operation variables
+//
+// Deprecated: variables are only recorded with WithVariables.
+func WithoutVariables() Option {
+	return func(c *config) {
+		c.variables = false
+	}
+}
+
+// WithMaxVariableLength truncates recorded variable values, defaults to 256 bytes
+func WithMaxVariableLength(n int) Option {
+	return func(c *config) {
+		c.maxVariableLength = n
+	}
+}
+
+// WithRedactedVariables redacts variables and input fields whose name contains
+// any of names, in addition to DefaultRedactedVariables
+func WithRedactedVariables(names ...string) Option {
+	return func(c *config) {
+		for _, name := range names {
+			c.redactedNames = append(c.redactedNames, strings.ToLower(name))
+		}
+	}
+}
+
+// WithFieldSpans creates a span for every resolver field, see WithMaxFieldDepth and WithFieldFilter
+func WithFieldSpans() Option {
+	return func(c *config) {
+		c.fieldSpans = true
+	}
+}
+
+// WithMaxFieldDepth only creates field spans up to depth fields deep, 0 means unlimited
+func WithMaxFieldDepth(depth int) Option {
+	return func(c *config) {
+		c.maxFieldDepth = depth
+	}
+}
+
+// WithFieldFilter selects the fields that get a span, defaults to fields with a resolver
+func WithFieldFilter(filter func(fc *graphql.FieldContext) bool) Option {
+	return func(c *config) {
+		c.fieldFilter = filter
+	}
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{
+		tracerProvider:    otel.GetTracerProvider(),
+		maxVariableLength: 256,
+		redactedNames:     append([]string{}, DefaultRedactedVariables...),
+		fieldFilter: func(fc *graphql.FieldContext) bool {
+			return fc.IsResolver
+		},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *config) tracer() trace.Tracer {
+	return c.tracerProvider.Tracer(instrumentationName)
+}
+
+func (c *config) traceField(fc *graphql.FieldContext) bool {
+	if c.maxFieldDepth > 0 && fieldDepth(fc.Path()) > c.maxFieldDepth {
+		return false
+	}
+	return c.fieldFilter(fc)
+}
+
+func (c *config) sensitive(name string) bool {
+	return containsAny(name, c.redactedNames)
+}
+
+func (c *config) variableValue(name string, value any) string {
+	if c.sensitive(name) {
+		return redacted
+	}
+	v := redactValue(value, c.sensitive)
+	if c.maxVariableLength > 0 && len(v) > c.maxVariableLength {
+		v = v[:c.maxVariableLength]
+	}
+	return v
+}
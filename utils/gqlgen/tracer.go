@@ -0,0 +1,223 @@
+package gqlgen
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationNameKey   = attribute.Key("graphql.operation.name")
+	OperationTypeKey   = attribute.Key("graphql.operation.type")
+	DocumentKey        = attribute.Key("graphql.document")
+	ComplexityKey      = attribute.Key("graphql.complexity")
+	ComplexityLimitKey = attribute.Key("graphql.complexity_limit")
+	VariablesPrefix    = "graphql.variables."
+	FieldNameKey       = attribute.Key("graphql.field.name")
+	FieldPathKey       = attribute.Key("graphql.field.path")
+	FieldTypeKey       = attribute.Key("graphql.field.type")
+	FieldAliasKey      = attribute.Key("graphql.field.alias")
+
+	redacted = "[REDACTED]"
+	// maxVariables caps the variables recorded by WithVariables
+	maxVariables = 32
+)
+
+// Tracer is a gqlgen extension creating a span per GraphQL operation and,
+// optionally, per resolved field
+//
+//	srv := handler.NewDefaultServer(schema)
+//	srv.Use(gqlgen.NewTracer())
+type Tracer struct {
+	config *config
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.ResponseInterceptor
+	graphql.FieldInterceptor
+} = &Tracer{}
+
+// NewTracer creates the extension configured with options
+func NewTracer(opts ...Option) *Tracer {
+	return &Tracer{config: newConfig(opts)}
+}
+
+func (t *Tracer) ExtensionName() string {
+	return "OpenTelemetryTracer"
+}
+
+func (t *Tracer) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+// InterceptResponse wraps every response, subscriptions get a span per message
+func (t *Tracer) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	if !graphql.HasOperationContext(ctx) {
+		return next(ctx)
+	}
+	opCtx := graphql.GetOperationContext(ctx)
+
+	ctx, span := t.config.tracer().Start(ctx, operationName(opCtx),
+		trace.WithAttributes(t.operationAttributes(ctx, opCtx)...),
+	)
+	defer span.End()
+
+	resp := next(ctx)
+	if resp != nil {
+		recordErrors(span, resp.Errors)
+	}
+	return resp
+}
+
+// InterceptField wraps every field, only fields accepted by the filter get a span
+func (t *Tracer) InterceptField(ctx context.Context, next graphql.Resolver) (any, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if !t.config.fieldSpans || fc == nil || !t.config.traceField(fc) {
+		return next(ctx)
+	}
+
+	path := fc.Path().String()
+	ctx, span := t.config.tracer().Start(ctx, fmt.Sprintf("%s.%s", fc.Object, fc.Field.Name),
+		trace.WithAttributes(
+			FieldNameKey.String(fc.Field.Name),
+			FieldPathKey.String(path),
+			FieldAliasKey.String(fc.Field.Alias),
+		),
+	)
+	defer span.End()
+	if fc.Field.Definition != nil {
+		span.SetAttributes(FieldTypeKey.String(fc.Field.Definition.Type.String()))
+	}
+
+	res, err := next(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	recordErrors(span, graphql.GetFieldErrors(ctx, fc))
+	return res, err
+}
+
+func (t *Tracer) operationAttributes(ctx context.Context, opCtx *graphql.OperationContext) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	if opCtx.OperationName != "" {
+		attrs = append(attrs, OperationNameKey.String(opCtx.OperationName))
+	}
+	if opCtx.Operation != nil {
+		attrs = append(attrs, OperationTypeKey.String(string(opCtx.Operation.Operation)))
+	}
+	if t.config.document {
+		attrs = append(attrs, DocumentKey.String(opCtx.RawQuery))
+	}
+	if stats := extension.GetComplexityStats(ctx); stats != nil {
+		attrs = append(attrs,
+			ComplexityKey.Int(stats.Complexity),
+			ComplexityLimitKey.Int(stats.ComplexityLimit),
+		)
+	}
+	if t.config.variables {
+		names := make([]string, 0, len(opCtx.Variables))
+		for name := range opCtx.Variables {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		if len(names) > maxVariables {
+			names = names[:maxVariables]
+		}
+		for _, name := range names {
+			attrs = append(attrs, attribute.String(VariablesPrefix+name, t.config.variableValue(name, opCtx.Variables[name])))
+		}
+	}
+	return attrs
+}
+
+// operationName returns the span name, e.g. "query GetUser"
+func operationName(opCtx *graphql.OperationContext) string {
+	operationType := "operation"
+	if opCtx.Operation != nil {
+		operationType = string(opCtx.Operation.Operation)
+	}
+	if opCtx.OperationName == "" {
+		return operationType
+	}
+	return operationType + " " + opCtx.OperationName
+}
+
+// fieldDepth returns the number of fields in path, ignoring list indexes
+func fieldDepth(path ast.Path) int {
+	depth := 0
+	for _, element := range path {
+		if _, ok := element.(ast.PathName); ok {
+			depth++
+		}
+	}
+	return depth
+}
+
+func recordErrors(span trace.Span, errs gqlerror.List) {
+	if len(errs) == 0 {
+		return
+	}
+	for _, err := range errs {
+		span.RecordError(err)
+	}
+	span.SetStatus(codes.Error, errs.Error())
+}
+
+// redactValue encodes a variable value, replacing sensitive values with a placeholder
+func redactValue(value any, sensitive func(name string) bool) string {
+	value = redactNested(value, sensitive)
+	if s, ok := value.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(b)
+}
+
+func redactNested(value any, sensitive func(name string) bool) any {
+	switch v := value.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, nested := range v {
+			if sensitive(key) {
+				out[key] = redacted
+				continue
+			}
+			out[key] = redactNested(nested, sensitive)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, nested := range v {
+			out[i] = redactNested(nested, sensitive)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// containsAny reports whether name contains any of parts, ignoring case
+func containsAny(name string, parts []string) bool {
+	name = strings.ToLower(name)
+	for _, part := range parts {
+		if strings.Contains(name, part) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,121 @@
+package gqlgen_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/testserver"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2/ast"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/weeb-vip/go-tracing-lib/utils/gqlgen"
+)
+
+func post(handler http.Handler, body string) {
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+}
+
+func TestTracer(t *testing.T) {
+	t.Run("Should create operation and field spans", func(t *testing.T) {
+		a := assert.New(t)
+		recorder := tracetest.NewSpanRecorder()
+
+		srv := testserver.New()
+		srv.AddTransport(transport.POST{})
+		srv.Use(extension.FixedComplexityLimit(100))
+		srv.Use(gqlgen.NewTracer(
+			gqlgen.WithTracerProvider(trace.NewTracerProvider(trace.WithSpanProcessor(recorder))),
+			gqlgen.WithFieldSpans(),
+			gqlgen.WithFieldFilter(func(fc *graphql.FieldContext) bool { return true }),
+			gqlgen.WithVariables(),
+		))
+
+		post(srv, `{"query":"query GetName($token: Int!) { name find(id: $token) }","operationName":"GetName","variables":{"token":1}}`)
+
+		spans := recorder.Ended()
+		a.Len(spans, 2)
+		a.Equal("Query.name", spans[0].Name())
+		a.Contains(spans[0].Attributes(), attribute.String("graphql.field.path", "name"))
+
+		operation := spans[1]
+		a.Equal("query GetName", operation.Name())
+		a.Equal(operation.SpanContext().SpanID(), spans[0].Parent().SpanID())
+		a.Contains(operation.Attributes(), attribute.String("graphql.operation.name", "GetName"))
+		a.Contains(operation.Attributes(), attribute.String("graphql.operation.type", "query"))
+		a.Contains(operation.Attributes(), attribute.Int("graphql.complexity_limit", 100))
+		a.Contains(operation.Attributes(), attribute.String("graphql.variables.token", "[REDACTED]"))
+		a.Equal(codes.Unset, operation.Status().Code)
+	})
+
+	t.Run("Should only record variables when asked, cut to the max length", func(t *testing.T) {
+		a := assert.New(t)
+		query := `{"query":"query GetName($id: Int!) { name find(id: $id) }","operationName":"GetName","variables":{"id":12345678}}`
+
+		for _, opts := range [][]gqlgen.Option{nil, {gqlgen.WithVariables(), gqlgen.WithMaxVariableLength(4)}} {
+			recorder := tracetest.NewSpanRecorder()
+			srv := testserver.New()
+			srv.AddTransport(transport.POST{})
+			srv.Use(gqlgen.NewTracer(append(opts, gqlgen.WithTracerProvider(trace.NewTracerProvider(trace.WithSpanProcessor(recorder))))...))
+
+			post(srv, query)
+
+			operation := recorder.Ended()[len(recorder.Ended())-1]
+			if opts == nil {
+				for _, attr := range operation.Attributes() {
+					a.NotContains(string(attr.Key), "graphql.variables.")
+				}
+				continue
+			}
+			a.Contains(operation.Attributes(), attribute.String("graphql.variables.id", "1234"))
+		}
+	})
+
+	t.Run("Should record GraphQL errors", func(t *testing.T) {
+		a := assert.New(t)
+		recorder := tracetest.NewSpanRecorder()
+
+		srv := testserver.NewError()
+		srv.AddTransport(transport.POST{})
+		srv.Use(gqlgen.NewTracer(gqlgen.WithTracerProvider(trace.NewTracerProvider(trace.WithSpanProcessor(recorder)))))
+
+		post(srv, `{"query":"{ name }"}`)
+
+		spans := recorder.Ended()
+		a.Len(spans, 1)
+		a.Equal("query", spans[0].Name())
+		a.Equal(codes.Error, spans[0].Status().Code)
+		a.Contains(spans[0].Status().Description, "resolver error")
+	})
+
+	t.Run("Should skip fields deeper than the maximum depth", func(t *testing.T) {
+		a := assert.New(t)
+		recorder := tracetest.NewSpanRecorder()
+
+		tracer := gqlgen.NewTracer(
+			gqlgen.WithTracerProvider(trace.NewTracerProvider(trace.WithSpanProcessor(recorder))),
+			gqlgen.WithFieldSpans(),
+			gqlgen.WithMaxFieldDepth(1),
+		)
+
+		parent := &graphql.FieldContext{Field: graphql.CollectedField{Field: &ast.Field{Alias: "user"}}, IsResolver: true}
+		child := &graphql.FieldContext{Parent: parent, Field: graphql.CollectedField{Field: &ast.Field{Alias: "friends"}}, IsResolver: true}
+		ctx := graphql.WithFieldContext(context.Background(), parent)
+		ctx = graphql.WithFieldContext(ctx, child)
+
+		_, err := tracer.InterceptField(ctx, func(ctx context.Context) (any, error) { return nil, nil })
+		a.NoError(err)
+		a.Empty(recorder.Ended())
+	})
+}
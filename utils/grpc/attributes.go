@@ -0,0 +1,84 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	"go.opentelemetry.io/otel/trace"
+	grpc_codes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// spanInfo returns the span name and RPC attributes for a full method name like /package.Service/Method
+func spanInfo(fullMethod string) (string, []attribute.KeyValue) {
+	name := strings.TrimPrefix(fullMethod, "/")
+	attrs := []attribute.KeyValue{semconv.RPCSystemGRPC}
+
+	service, method, ok := strings.Cut(name, "/")
+	if !ok {
+		return name, attrs
+	}
+	if service != "" {
+		attrs = append(attrs, semconv.RPCService(service))
+	}
+	if method != "" {
+		attrs = append(attrs, semconv.RPCMethod(method))
+	}
+	return name, attrs
+}
+
+// peerAttributes returns the address of the remote side of the connection in ctx
+func peerAttributes(ctx context.Context) []attribute.KeyValue {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return nil
+	}
+	host, port, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return nil
+	}
+	attrs := []attribute.KeyValue{semconv.NetSockPeerAddr(host)}
+	if p, err := strconv.Atoi(port); err == nil {
+		attrs = append(attrs, semconv.NetSockPeerPort(p))
+	}
+	return attrs
+}
+
+// finishSpan records the gRPC status of err on span
+func finishSpan(span trace.Span, err error, server bool) {
+	s, ok := status.FromError(err)
+	if !ok {
+		// context errors of the caller, e.g. seen by the client stream, are no status yet
+		s = status.FromContextError(err)
+	}
+	span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(s.Code())))
+	if err == nil {
+		return
+	}
+
+	span.RecordError(err)
+	if !server || isServerError(s.Code()) {
+		span.SetStatus(codes.Error, s.Message())
+	}
+}
+
+// isServerError reports whether code means the server failed, other codes are caused by the client
+func isServerError(code grpc_codes.Code) bool {
+	switch code {
+	case grpc_codes.Unknown,
+		grpc_codes.DeadlineExceeded,
+		grpc_codes.Unimplemented,
+		grpc_codes.Internal,
+		grpc_codes.Unavailable,
+		grpc_codes.DataLoss:
+		return true
+	default:
+		return false
+	}
+}
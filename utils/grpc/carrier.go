@@ -0,0 +1,48 @@
+package grpc
+
+import (
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// MetadataCarrier carries trace context in gRPC metadata
+type MetadataCarrier struct {
+	md metadata.MD
+}
+
+// NewMetadataCarrier creates a carrier over md, metadata keys are lower case
+func NewMetadataCarrier(md metadata.MD) *MetadataCarrier {
+	if md == nil {
+		md = metadata.MD{}
+	}
+	return &MetadataCarrier{md: md}
+}
+
+// Get retrieves the first value for key
+func (c *MetadataCarrier) Get(key string) string {
+	values := c.md.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// Set sets the value for key, replacing existing values
+func (c *MetadataCarrier) Set(key string, value string) {
+	c.md.Set(strings.ToLower(key), value)
+}
+
+// Keys returns all metadata keys
+func (c *MetadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c.md))
+	for key := range c.md {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Metadata returns the underlying metadata
+func (c *MetadataCarrier) Metadata() metadata.MD {
+	return c.md
+}
@@ -0,0 +1,135 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	grpc_codes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+// UnaryClientInterceptor starts a client span for every unary call and
+// propagates it in the outgoing metadata
+func UnaryClientInterceptor(opts ...Option) grpc.UnaryClientInterceptor {
+	config := newConfig(opts)
+
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		ctx, span := config.startClientSpan(ctx, method, cc)
+		defer span.End()
+
+		err := invoker(ctx, method, req, reply, cc, callOpts...)
+		finishSpan(span, err, false)
+		return err
+	}
+}
+
+// StreamClientInterceptor starts a client span for every streaming call and
+// propagates it in the outgoing metadata. The span ends with the outcome of
+// the stream seen by RecvMsg, SendMsg, Header or CloseSend, or when the caller
+// cancels the stream before seeing one.
+func StreamClientInterceptor(opts ...Option) grpc.StreamClientInterceptor {
+	config := newConfig(opts)
+
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, callOpts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx, span := config.startClientSpan(ctx, method, cc)
+
+		stream, err := streamer(ctx, desc, cc, method, callOpts...)
+		if err != nil {
+			finishSpan(span, err, false)
+			span.End()
+			return nil, err
+		}
+
+		s := &clientStream{ClientStream: stream, desc: desc, span: span}
+		go func() {
+			// the stream context is done once the stream is, whatever its outcome,
+			// so only a cancelled caller context ends the span from here
+			<-stream.Context().Done()
+			if err := ctx.Err(); err != nil {
+				s.finish(err)
+			}
+		}()
+		return s, nil
+	}
+}
+
+func (c *config) startClientSpan(ctx context.Context, method string, cc *grpc.ClientConn) (context.Context, trace.Span) {
+	name, attrs := spanInfo(method)
+	ctx, span := c.tracer().Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	}
+	carrier := NewMetadataCarrier(md)
+	c.propagator.Inject(ctx, carrier)
+	ctx = metadata.NewOutgoingContext(ctx, carrier.Metadata())
+
+	return ctx, span
+}
+
+// clientStream ends the span when the stream completes
+type clientStream struct {
+	grpc.ClientStream
+	desc *grpc.StreamDesc
+	span trace.Span
+	once sync.Once
+}
+
+func (s *clientStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	switch {
+	case errors.Is(err, io.EOF):
+		s.finish(nil)
+	case err != nil:
+		s.finish(err)
+	case !s.desc.ServerStreams:
+		// the single response of a unary or client streaming call ends the stream
+		s.finish(nil)
+	}
+	return err
+}
+
+func (s *clientStream) SendMsg(m any) error {
+	err := s.ClientStream.SendMsg(m)
+	if err != nil && !errors.Is(err, io.EOF) {
+		s.finish(err)
+	}
+	return err
+}
+
+func (s *clientStream) CloseSend() error {
+	err := s.ClientStream.CloseSend()
+	if err != nil {
+		s.finish(err)
+	}
+	return err
+}
+
+func (s *clientStream) Header() (metadata.MD, error) {
+	md, err := s.ClientStream.Header()
+	if err != nil {
+		s.finish(err)
+	}
+	return md, err
+}
+
+func (s *clientStream) finish(err error) {
+	s.once.Do(func() {
+		defer s.span.End()
+		if errors.Is(err, context.Canceled) {
+			// the caller stopped reading and cancelled the stream, which is not a failure
+			s.span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(grpc_codes.Canceled)))
+			return
+		}
+		finishSpan(s.span, err, false)
+	})
+}
@@ -0,0 +1,236 @@
+package grpc_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/weeb-vip/go-tracing-lib/logger"
+	tracing_grpc "github.com/weeb-vip/go-tracing-lib/utils/grpc"
+)
+
+// loggingHealthServer logs from the handler context before answering
+type loggingHealthServer struct {
+	*health.Server
+}
+
+func (s *loggingHealthServer) Check(ctx context.Context, req *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	l := logger.FromContext(ctx)
+	l.Info().Msg("checking health")
+	return s.Server.Check(ctx, req)
+}
+
+// countDesc describes a server stream answering a request with three responses
+var countDesc = grpc.ServiceDesc{
+	ServiceName: "test.Counter",
+	HandlerType: (*any)(nil),
+	Streams: []grpc.StreamDesc{{
+		StreamName:    "Count",
+		ServerStreams: true,
+		Handler: func(_ any, stream grpc.ServerStream) error {
+			if err := stream.RecvMsg(&grpc_health_v1.HealthCheckRequest{}); err != nil {
+				return err
+			}
+			for i := 0; i < 3; i++ {
+				if err := stream.SendMsg(&grpc_health_v1.HealthCheckResponse{}); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}},
+}
+
+// slowEOFStream returns io.EOF late, after gRPC cancelled the stream context
+type slowEOFStream struct {
+	grpc.ClientStream
+}
+
+func (s slowEOFStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	if errors.Is(err, io.EOF) {
+		<-s.Context().Done()
+		time.Sleep(10 * time.Millisecond)
+	}
+	return err
+}
+
+func setup(t *testing.T) (grpc_health_v1.HealthClient, *health.Server, *tracetest.SpanRecorder) {
+	conn, healthServer, recorder := setupConn(t)
+	return grpc_health_v1.NewHealthClient(conn), healthServer, recorder
+}
+
+func setupConn(t *testing.T, dialOpts ...grpc.DialOption) (*grpc.ClientConn, *health.Server, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	opts := []tracing_grpc.Option{
+		tracing_grpc.WithTracerProvider(trace.NewTracerProvider(trace.WithSpanProcessor(recorder))),
+		tracing_grpc.WithPropagator(propagation.TraceContext{}),
+	}
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(tracing_grpc.UnaryServerInterceptor(opts...)),
+		grpc.StreamInterceptor(tracing_grpc.StreamServerInterceptor(opts...)),
+	)
+	healthServer := health.NewServer()
+	grpc_health_v1.RegisterHealthServer(server, &loggingHealthServer{Server: healthServer})
+	server.RegisterService(&countDesc, nil)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet", append(dialOpts,
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(tracing_grpc.UnaryClientInterceptor(opts...)),
+		grpc.WithStreamInterceptor(tracing_grpc.StreamClientInterceptor(opts...)),
+	)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return conn, healthServer, recorder
+}
+
+func spanByKind(spans []trace.ReadOnlySpan, kind oteltrace.SpanKind) trace.ReadOnlySpan {
+	for _, span := range spans {
+		if span.SpanKind() == kind {
+			return span
+		}
+	}
+	return nil
+}
+
+func TestUnaryInterceptors(t *testing.T) {
+	t.Run("Should propagate the client span to the server", func(t *testing.T) {
+		a := assert.New(t)
+		buf := &bytes.Buffer{}
+		logger.Logger(logger.WithOutput(buf))
+		defer logger.Reset()
+
+		client, _, recorder := setup(t)
+
+		_, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+		a.NoError(err)
+
+		spans := recorder.Ended()
+		a.Len(spans, 2)
+		server := spanByKind(spans, oteltrace.SpanKindServer)
+		clientSpan := spanByKind(spans, oteltrace.SpanKindClient)
+		a.Equal("grpc.health.v1.Health/Check", server.Name())
+		a.Equal(clientSpan.SpanContext().TraceID(), server.SpanContext().TraceID())
+		a.Equal(clientSpan.SpanContext().SpanID(), server.Parent().SpanID())
+		a.Contains(server.Attributes(), attribute.String("rpc.service", "grpc.health.v1.Health"))
+		a.Contains(server.Attributes(), attribute.String("rpc.method", "Check"))
+		a.Contains(server.Attributes(), attribute.Int("rpc.grpc.status_code", 0))
+		a.Contains(buf.String(), `"traceID":"`+server.SpanContext().TraceID().String()+`"`)
+	})
+
+	t.Run("Should record the status of failed calls", func(t *testing.T) {
+		a := assert.New(t)
+		client, _, recorder := setup(t)
+
+		_, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "unknown"})
+		a.Error(err)
+
+		spans := recorder.Ended()
+		server := spanByKind(spans, oteltrace.SpanKindServer)
+		clientSpan := spanByKind(spans, oteltrace.SpanKindClient)
+		// NotFound is caused by the client, so only the client span is an error
+		a.Contains(server.Attributes(), attribute.Int("rpc.grpc.status_code", 5))
+		a.Equal(codes.Unset, server.Status().Code)
+		a.Equal(codes.Error, clientSpan.Status().Code)
+	})
+}
+
+func TestStreamInterceptors(t *testing.T) {
+	t.Run("Should trace server streams", func(t *testing.T) {
+		a := assert.New(t)
+		client, _, recorder := setup(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		stream, err := client.Watch(ctx, &grpc_health_v1.HealthCheckRequest{})
+		a.NoError(err)
+		resp, err := stream.Recv()
+		a.NoError(err)
+		a.Equal(grpc_health_v1.HealthCheckResponse_SERVING, resp.Status)
+		cancel()
+
+		a.Eventually(func() bool { return len(recorder.Ended()) == 2 }, time.Second, 10*time.Millisecond)
+		spans := recorder.Ended()
+		server := spanByKind(spans, oteltrace.SpanKindServer)
+		clientSpan := spanByKind(spans, oteltrace.SpanKindClient)
+		a.Equal("grpc.health.v1.Health/Watch", server.Name())
+		a.Equal(clientSpan.SpanContext().SpanID(), server.Parent().SpanID())
+		a.Equal(codes.Unset, clientSpan.Status().Code)
+	})
+
+	t.Run("Should record the deadline of the caller as DEADLINE_EXCEEDED", func(t *testing.T) {
+		a := assert.New(t)
+		client, _, recorder := setup(t)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		stream, err := client.Watch(ctx, &grpc_health_v1.HealthCheckRequest{})
+		a.NoError(err)
+		_, err = stream.Recv()
+		a.NoError(err)
+
+		a.Eventually(func() bool { return spanByKind(recorder.Ended(), oteltrace.SpanKindClient) != nil }, time.Second, 10*time.Millisecond)
+		clientSpan := spanByKind(recorder.Ended(), oteltrace.SpanKindClient)
+		a.Contains(clientSpan.Attributes(), attribute.Int("rpc.grpc.status_code", 4))
+		a.Equal(codes.Error, clientSpan.Status().Code)
+	})
+
+	t.Run("Should end streams read to the end without an error", func(t *testing.T) {
+		a := assert.New(t)
+		// the stream context is done before RecvMsg returns io.EOF, which must not end the span as cancelled
+		conn, _, recorder := setupConn(t, grpc.WithChainStreamInterceptor(
+			func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+				stream, err := streamer(ctx, desc, cc, method, opts...)
+				return slowEOFStream{ClientStream: stream}, err
+			},
+		))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		stream, err := conn.NewStream(ctx, &countDesc.Streams[0], "/test.Counter/Count")
+		a.NoError(err)
+		a.NoError(stream.SendMsg(&grpc_health_v1.HealthCheckRequest{}))
+		a.NoError(stream.CloseSend())
+		received := 0
+		for {
+			if err := stream.RecvMsg(&grpc_health_v1.HealthCheckResponse{}); err != nil {
+				a.ErrorIs(err, io.EOF)
+				break
+			}
+			received++
+		}
+		a.Equal(3, received)
+
+		a.Eventually(func() bool { return len(recorder.Ended()) == 2 }, time.Second, 10*time.Millisecond)
+		clientSpan := spanByKind(recorder.Ended(), oteltrace.SpanKindClient)
+		a.Equal("test.Counter/Count", clientSpan.Name())
+		a.Equal(codes.Unset, clientSpan.Status().Code)
+		a.Contains(clientSpan.Attributes(), attribute.Int("rpc.grpc.status_code", 0))
+	})
+}
@@ -0,0 +1,59 @@
+package grpc
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/weeb-vip/go-tracing-lib/logger"
+	"github.com/weeb-vip/go-tracing-lib/providers"
+)
+
+const instrumentationName = "github.com/weeb-vip/go-tracing-lib/utils/grpc"
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	propagator     propagation.TextMapPropagator
+	logFormats     []providers.LogFormat
+}
+
+// Option configures the interceptors
+type Option func(*config)
+
+// WithTracerProvider sets the tracer provider, defaults to the global provider
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = provider
+	}
+}
+
+// WithPropagator sets the propagator used for metadata, defaults to the global propagator
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagator = propagator
+	}
+}
+
+// WithLogFormats selects the trace identifier formats of the server logger,
+// defaults to logger.DefaultTraceFormats
+func WithLogFormats(formats ...providers.LogFormat) Option {
+	return func(c *config) {
+		c.logFormats = formats
+	}
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{
+		tracerProvider: otel.GetTracerProvider(),
+		propagator:     otel.GetTextMapPropagator(),
+		logFormats:     logger.DefaultTraceFormats,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *config) tracer() trace.Tracer {
+	return c.tracerProvider.Tracer(instrumentationName)
+}
@@ -0,0 +1,68 @@
+package grpc
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/weeb-vip/go-tracing-lib/logger"
+)
+
+// UnaryServerInterceptor starts a server span for every unary call, continuing
+// the trace found in the incoming metadata, and attaches a trace-aware logger
+func UnaryServerInterceptor(opts ...Option) grpc.UnaryServerInterceptor {
+	config := newConfig(opts)
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, span := config.startServerSpan(ctx, info.FullMethod)
+		defer span.End()
+
+		resp, err := handler(ctx, req)
+		finishSpan(span, err, true)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor starts a server span for every streaming call, continuing
+// the trace found in the incoming metadata, and attaches a trace-aware logger
+func StreamServerInterceptor(opts ...Option) grpc.StreamServerInterceptor {
+	config := newConfig(opts)
+
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, span := config.startServerSpan(stream.Context(), info.FullMethod)
+		defer span.End()
+
+		err := handler(srv, &serverStream{ServerStream: stream, ctx: ctx})
+		finishSpan(span, err, true)
+		return err
+	}
+}
+
+func (c *config) startServerSpan(ctx context.Context, fullMethod string) (context.Context, trace.Span) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = c.propagator.Extract(ctx, NewMetadataCarrier(md.Copy()))
+
+	name, attrs := spanInfo(fullMethod)
+	ctx, span := c.tracer().Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(attrs...),
+		trace.WithAttributes(peerAttributes(ctx)...),
+	)
+
+	// attach tracer context to logger
+	ctx = logger.ContextWithTraceFormats(ctx, c.logFormats...)
+
+	return ctx, span
+}
+
+// serverStream overrides the context of a grpc.ServerStream
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -1,13 +1,107 @@
 package http_client
 
 import (
-	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
-func NewHttpClient() http.Client {
+const (
+	URLTemplateKey = attribute.Key("url.template")
+	ResendCountKey = attribute.Key("http.request.resend_count")
+)
+
+// NewHttpClient returns a client sending requests in client spans and
+// propagating their context in the request headers
+func NewHttpClient(opts ...Option) http.Client {
+	c := newConfig(opts)
 	client := http.Client{
-		Transport: otelhttp.NewTransport(http.DefaultTransport),
+		Transport: c.transport(),
+		Timeout:   c.timeout,
 	}
 	return client
 }
+
+// NewTransport returns the transport of NewHttpClient, for clients created elsewhere.
+// WithTimeout does not apply to it.
+func NewTransport(opts ...Option) http.RoundTripper {
+	return newConfig(opts).transport()
+}
+
+func (c *config) transport() http.RoundTripper {
+	otelOptions := []otelhttp.Option{
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			if template, ok := c.urlTemplate(r.URL.Path); ok {
+				return r.Method + " " + template
+			}
+			return "HTTP " + r.Method
+		}),
+	}
+	if c.tracerProvider != nil {
+		otelOptions = append(otelOptions, otelhttp.WithTracerProvider(c.tracerProvider))
+	}
+	if c.propagator != nil {
+		otelOptions = append(otelOptions, otelhttp.WithPropagators(c.propagator))
+	}
+	if c.policy != nil {
+		// the context is injected by spanTransport, which knows the host
+		otelOptions = append(otelOptions, otelhttp.WithPropagators(propagation.NewCompositeTextMapPropagator()))
+	}
+	otelOptions = append(otelOptions, c.otelOptions...)
+
+	var transport http.RoundTripper = otelhttp.NewTransport(&spanTransport{base: c.base, config: c}, otelOptions...)
+	if c.maxAttempts > 1 || c.attemptTimeout > 0 {
+		transport = &retryTransport{next: transport, config: c}
+	}
+	return transport
+}
+
+// spanTransport sits below otelhttp and adds the attributes it does not know
+// about to the span of the request, and the trace context allowed by the
+// propagation policy to its headers
+type spanTransport struct {
+	base   http.RoundTripper
+	config *config
+}
+
+func (t *spanTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if t.config.policy != nil {
+		propagator := t.config.propagator
+		if propagator == nil {
+			propagator = otel.GetTextMapPropagator()
+		}
+		t.config.policy.Propagator(r.URL.Hostname(), propagator).Inject(r.Context(), propagation.HeaderCarrier(r.Header))
+	}
+
+	span := trace.SpanFromContext(r.Context())
+	if template, ok := t.config.urlTemplate(r.URL.Path); ok {
+		span.SetAttributes(URLTemplateKey.String(template))
+	}
+	if attempt, ok := r.Context().Value(attemptKey{}).(int); ok && attempt > 1 {
+		span.SetAttributes(ResendCountKey.Int(attempt - 1))
+	}
+	span.SetAttributes(headerAttributes("http.request.header.", r.Header, t.config.requestHeaders)...)
+
+	resp, err := t.base.RoundTrip(r)
+	if err != nil {
+		return resp, err
+	}
+	span.SetAttributes(headerAttributes("http.response.header.", resp.Header, t.config.responseHeaders)...)
+	return resp, nil
+}
+
+// headerAttributes returns an attribute per listed header present in headers
+func headerAttributes(prefix string, headers http.Header, names []string) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	for _, name := range names {
+		if values := headers.Values(name); len(values) > 0 {
+			attrs = append(attrs, attribute.StringSlice(prefix+strings.ToLower(name), values))
+		}
+	}
+	return attrs
+}
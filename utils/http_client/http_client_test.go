@@ -0,0 +1,155 @@
+package http_client_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/weeb-vip/go-tracing-lib/tracing/outbound"
+	"github.com/weeb-vip/go-tracing-lib/tracing/retry"
+	"github.com/weeb-vip/go-tracing-lib/utils/http_client"
+)
+
+func setup() ([]http_client.Option, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	return []http_client.Option{
+		http_client.WithTracerProvider(trace.NewTracerProvider(trace.WithSpanProcessor(recorder))),
+		http_client.WithPropagator(propagation.TraceContext{}),
+		http_client.WithRetryBackOff(func() retry.BackOff { return retry.ConstantBackOff{Interval: time.Millisecond} }),
+	}, recorder
+}
+
+func get(t *testing.T, client http.Client, url string) *http.Response {
+	resp, err := client.Get(url)
+	assert.NoError(t, err)
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return resp
+}
+
+func TestNewHttpClient(t *testing.T) {
+	t.Run("Should name spans from URL templates and capture listed headers", func(t *testing.T) {
+		a := assert.New(t)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			a.NotEmpty(r.Header.Get("traceparent"))
+			w.Header().Set("X-Request-Id", "abc")
+			w.Header().Set("Set-Cookie", "secret")
+		}))
+		defer server.Close()
+		opts, recorder := setup()
+
+		client := http_client.NewHttpClient(append(opts,
+			http_client.WithURLTemplates("/teams/{team}", "/users/{id}"),
+			http_client.WithResponseHeaders("X-Request-Id"),
+			http_client.WithTimeout(time.Second),
+		)...)
+		a.Equal(time.Second, client.Timeout)
+		get(t, client, server.URL+"/users/123")
+		get(t, client, server.URL+"/users/123/posts")
+
+		spans := recorder.Ended()
+		a.Len(spans, 2)
+		a.Equal("GET /users/{id}", spans[0].Name())
+		a.Contains(spans[0].Attributes(), attribute.String("url.template", "/users/{id}"))
+		a.Contains(spans[0].Attributes(), attribute.StringSlice("http.response.header.x-request-id", []string{"abc"}))
+		for _, attr := range spans[0].Attributes() {
+			a.NotEqual(attribute.Key("http.response.header.set-cookie"), attr.Key)
+		}
+		a.Equal("HTTP GET", spans[1].Name())
+	})
+
+	t.Run("Should retry failed attempts in a span each", func(t *testing.T) {
+		a := assert.New(t)
+		calls := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			a.Equal("payload", string(body))
+			if calls++; calls < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		}))
+		defer server.Close()
+		opts, recorder := setup()
+
+		client := http_client.NewHttpClient(append(opts, http_client.WithRetries(3))...)
+		req, _ := http.NewRequest(http.MethodPut, server.URL, strings.NewReader("payload"))
+		resp, err := client.Do(req)
+
+		a.NoError(err)
+		resp.Body.Close()
+		a.Equal(http.StatusOK, resp.StatusCode)
+		spans := recorder.Ended()
+		a.Len(spans, 3)
+		a.Contains(spans[0].Attributes(), attribute.Int("http.status_code", http.StatusServiceUnavailable))
+		a.Contains(spans[1].Attributes(), attribute.Int("http.request.resend_count", 1))
+		a.Contains(spans[2].Attributes(), attribute.Int("http.request.resend_count", 2))
+		a.Contains(spans[2].Attributes(), attribute.Int("http.status_code", http.StatusOK))
+	})
+
+	t.Run("Should not retry requests that are not idempotent", func(t *testing.T) {
+		a := assert.New(t)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+		opts, recorder := setup()
+
+		client := http_client.NewHttpClient(append(opts, http_client.WithRetries(3))...)
+		resp, err := client.Post(server.URL, "text/plain", strings.NewReader("payload"))
+
+		a.NoError(err)
+		resp.Body.Close()
+		a.Equal(http.StatusServiceUnavailable, resp.StatusCode)
+		a.Len(recorder.Ended(), 1)
+	})
+
+	t.Run("Should retry attempts that time out", func(t *testing.T) {
+		a := assert.New(t)
+		calls := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls++; calls == 1 {
+				<-r.Context().Done()
+			}
+		}))
+		defer server.Close()
+		opts, recorder := setup()
+
+		client := http_client.NewHttpClient(append(opts, http_client.WithRetries(2), http_client.WithAttemptTimeout(50*time.Millisecond))...)
+		resp := get(t, client, server.URL)
+
+		a.Equal(http.StatusOK, resp.StatusCode)
+		a.Len(recorder.Ended(), 2)
+	})
+}
+
+func TestPropagationPolicy(t *testing.T) {
+	t.Run("Should only propagate the context allowed for the host", func(t *testing.T) {
+		a := assert.New(t)
+		var headers []http.Header
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			headers = append(headers, r.Header)
+		}))
+		defer server.Close()
+		opts, recorder := setup()
+
+		client := http_client.NewHttpClient(append(opts, http_client.WithPropagationPolicy(outbound.New(
+			outbound.Allow("localhost", outbound.All),
+		)))...)
+		get(t, client, strings.Replace(server.URL, "127.0.0.1", "localhost", 1))
+		get(t, client, server.URL)
+
+		spans := recorder.Ended()
+		a.Equal("00-"+spans[0].SpanContext().TraceID().String()+"-"+spans[0].SpanContext().SpanID().String()+"-01", headers[0].Get("traceparent"))
+		a.Empty(headers[1].Get("traceparent"))
+		a.Len(spans, 2)
+	})
+}
@@ -0,0 +1,150 @@
+package http_client
+
+import (
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/weeb-vip/go-tracing-lib/tracing/outbound"
+	"github.com/weeb-vip/go-tracing-lib/tracing/retry"
+)
+
+type config struct {
+	tracerProvider  trace.TracerProvider
+	propagator      propagation.TextMapPropagator
+	policy          *outbound.Policy
+	base            http.RoundTripper
+	timeout         time.Duration
+	attemptTimeout  time.Duration
+	templates       []template
+	maxAttempts     int
+	newBackOff      func() retry.BackOff
+	retryIf         RetryPolicy
+	requestHeaders  []string
+	responseHeaders []string
+	otelOptions     []otelhttp.Option
+}
+
+// Option configures the client
+type Option func(*config)
+
+// WithTracerProvider sets the tracer provider, defaults to the global provider
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = provider
+	}
+}
+
+// WithPropagator sets the propagator used to inject the outgoing context,
+// defaults to the global propagator
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagator = propagator
+	}
+}
+
+// WithPropagationPolicy only propagates to each host the trace context its
+// rule allows, e.g. to keep baggage away from third parties
+func WithPropagationPolicy(policy *outbound.Policy) Option {
+	return func(c *config) {
+		c.policy = policy
+	}
+}
+
+// WithTransport sets the transport sending the requests, defaults to http.DefaultTransport
+func WithTransport(base http.RoundTripper) Option {
+	return func(c *config) {
+		c.base = base
+	}
+}
+
+// WithTimeout limits the time of a request including its retries and reading
+// the response body, see http.Client.Timeout. Defaults to no limit.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *config) {
+		c.timeout = timeout
+	}
+}
+
+// WithAttemptTimeout limits the time of each attempt including reading the response body
+func WithAttemptTimeout(timeout time.Duration) Option {
+	return func(c *config) {
+		c.attemptTimeout = timeout
+	}
+}
+
+// WithURLTemplates names spans after the first template matching the request
+// path, e.g. "/users/{id}" names a GET of /users/123 "GET /users/{id}". Each
+// {name} segment matches any single path segment. Requests matching no
+// template keep the default "HTTP GET" name.
+func WithURLTemplates(templates ...string) Option {
+	return func(c *config) {
+		for _, t := range templates {
+			c.templates = append(c.templates, parseTemplate(t))
+		}
+	}
+}
+
+// WithRetries sends requests up to attempts times, each attempt in its own
+// span. Only requests whose body can be sent again are retried, see
+// http.Request.GetBody.
+func WithRetries(attempts int) Option {
+	return func(c *config) {
+		c.maxAttempts = attempts
+	}
+}
+
+// WithRetryBackOff sets the policy deciding how long to wait between
+// attempts, newBackOff is called once per request. Defaults to
+// retry.NewExponentialBackOff.
+func WithRetryBackOff(newBackOff func() retry.BackOff) Option {
+	return func(c *config) {
+		c.newBackOff = newBackOff
+	}
+}
+
+// WithRetryIf decides which attempts are retried, defaults to DefaultRetryPolicy
+func WithRetryIf(policy RetryPolicy) Option {
+	return func(c *config) {
+		c.retryIf = policy
+	}
+}
+
+// WithRequestHeaders records the listed request headers on the spans as
+// http.request.header.<name> attributes
+func WithRequestHeaders(headers ...string) Option {
+	return func(c *config) {
+		c.requestHeaders = append(c.requestHeaders, headers...)
+	}
+}
+
+// WithResponseHeaders records the listed response headers on the spans as
+// http.response.header.<name> attributes
+func WithResponseHeaders(headers ...string) Option {
+	return func(c *config) {
+		c.responseHeaders = append(c.responseHeaders, headers...)
+	}
+}
+
+// WithOtelOptions passes additional options to otelhttp
+func WithOtelOptions(opts ...otelhttp.Option) Option {
+	return func(c *config) {
+		c.otelOptions = append(c.otelOptions, opts...)
+	}
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{
+		base:        http.DefaultTransport,
+		maxAttempts: 1,
+		newBackOff:  func() retry.BackOff { return retry.NewExponentialBackOff() },
+		retryIf:     DefaultRetryPolicy,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
@@ -0,0 +1,121 @@
+package http_client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/weeb-vip/go-tracing-lib/tracing/retry"
+)
+
+// RetryPolicy decides whether an attempt of req is retried given its response or error
+type RetryPolicy func(req *http.Request, resp *http.Response, err error) bool
+
+// DefaultRetryPolicy retries idempotent requests that failed without a
+// response or got a 429, 502, 503 or 504 status. Requests are idempotent when
+// their method is or when they have an Idempotency-Key header.
+func DefaultRetryPolicy(req *http.Request, resp *http.Response, err error) bool {
+	if !idempotent(req) {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func idempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get("Idempotency-Key") != ""
+}
+
+type attemptKey struct{}
+
+// retryTransport sends a request up to WithRetries times through next, which
+// starts a span per attempt
+type retryTransport struct {
+	next   http.RoundTripper
+	config *config
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	backOff := t.config.newBackOff()
+	backOff.Reset()
+
+	for attempt := 1; ; attempt++ {
+		resp, err := t.attempt(req, attempt)
+		if attempt >= t.config.maxAttempts || ctx.Err() != nil || !replayable(req) || !t.config.retryIf(req, resp, err) {
+			return resp, err
+		}
+		wait := backOff.NextBackOff()
+		if wait == retry.Stop {
+			return resp, err
+		}
+		if resp != nil {
+			// ends the span of the attempt and frees the connection
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// attempt sends a copy of req, with a fresh body after the first attempt
+func (t *retryTransport) attempt(req *http.Request, attempt int) (*http.Response, error) {
+	ctx := context.WithValue(req.Context(), attemptKey{}, attempt)
+	cancel := context.CancelFunc(func() {})
+	if t.config.attemptTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, t.config.attemptTimeout)
+	}
+
+	r := req.Clone(ctx)
+	if attempt > 1 && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		r.Body = body
+	}
+
+	resp, err := t.next.RoundTrip(r)
+	if err != nil {
+		cancel()
+		return resp, err
+	}
+	// the attempt lasts until its body is closed
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// replayable reports whether the body of req can be sent again
+func replayable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
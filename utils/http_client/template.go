@@ -0,0 +1,49 @@
+package http_client
+
+import (
+	"strings"
+)
+
+// template is a URL path template split in segments
+type template struct {
+	raw      string
+	segments []string
+}
+
+func parseTemplate(raw string) template {
+	return template{raw: raw, segments: strings.Split(strings.Trim(raw, "/"), "/")}
+}
+
+// match reports whether path matches the template, {name} segments match any non-empty segment
+func (t template) match(path string) bool {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) != len(t.segments) {
+		return false
+	}
+	for i, segment := range t.segments {
+		if isParameter(segment) {
+			if segments[i] == "" {
+				return false
+			}
+			continue
+		}
+		if segment != segments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func isParameter(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+// urlTemplate returns the first template matching path
+func (c *config) urlTemplate(path string) (string, bool) {
+	for _, t := range c.templates {
+		if t.match(path) {
+			return t.raw, true
+		}
+	}
+	return "", false
+}
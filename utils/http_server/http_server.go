@@ -0,0 +1,159 @@
+package http_server
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/weeb-vip/go-tracing-lib/logger"
+	"github.com/weeb-vip/go-tracing-lib/tracing/inbound"
+)
+
+// NewHandler wraps handler with otelhttp. Spans are named after the matched
+// route, falling back to operation, and the request context carries a
+// trace-aware logger. Routes are only known when handler is a *http.ServeMux,
+// see WithRouteResolver otherwise, and WithTrustBoundary for public endpoints.
+func NewHandler(handler http.Handler, operation string, opts ...Option) http.Handler {
+	config := newConfig(opts)
+	resolver := config.routeResolver
+	if mux, ok := handler.(*http.ServeMux); ok && resolver == nil {
+		resolver = MuxRoutes(mux)
+	}
+
+	otelOptions := []otelhttp.Option{
+		otelhttp.WithSpanNameFormatter(func(operation string, r *http.Request) string {
+			return spanName(operation, r.Method, routePattern(r))
+		}),
+	}
+	tracerProvider := config.tracerProvider
+	if config.boundary != nil {
+		if tracerProvider == nil {
+			tracerProvider = otel.GetTracerProvider()
+		}
+		tracerProvider = &incomingTracerProvider{TracerProvider: tracerProvider}
+	}
+	if tracerProvider != nil {
+		otelOptions = append(otelOptions, otelhttp.WithTracerProvider(tracerProvider))
+	}
+	if config.propagator != nil {
+		otelOptions = append(otelOptions, otelhttp.WithPropagators(config.propagator))
+	}
+	for _, filter := range config.filters {
+		otelOptions = append(otelOptions, otelhttp.WithFilter(filter))
+	}
+	if config.boundary != nil {
+		// the context is extracted before otelhttp, see trustBoundary
+		otelOptions = append(otelOptions, otelhttp.WithPropagators(propagation.NewCompositeTextMapPropagator()))
+	}
+	otelOptions = append(otelOptions, config.otelOptions...)
+
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if pattern := routePattern(r); pattern != "" {
+			trace.SpanFromContext(ctx).SetAttributes(semconv.HTTPRoute(route(pattern)))
+		}
+
+		// attach tracer context to logger
+		ctx = logger.ContextWithTraceFormats(ctx, config.logFormats...)
+
+		handler.ServeHTTP(w, r.WithContext(ctx))
+	})
+
+	traced := otelhttp.NewHandler(inner, operation, otelOptions...)
+	if config.boundary != nil {
+		traced = trustBoundary(traced, config)
+	}
+	if resolver != nil {
+		traced = resolveRoute(traced, resolver)
+	}
+	return traced
+}
+
+type incomingKey struct{}
+
+type patternKey struct{}
+
+// MuxRoutes resolves routes with mux, for WithRouteResolver when mux is
+// wrapped by other handlers
+func MuxRoutes(mux *http.ServeMux) func(r *http.Request) string {
+	return func(r *http.Request) string {
+		_, pattern := mux.Handler(r)
+		return pattern
+	}
+}
+
+// resolveRoute resolves the route pattern of the request once, before
+// otelhttp names the span, and stores it in the request context
+func resolveRoute(next http.Handler, resolver func(r *http.Request) string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if pattern := resolver(r); pattern != "" {
+			r = r.WithContext(context.WithValue(r.Context(), patternKey{}, pattern))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// trustBoundary extracts the trace context the boundary accepts for otelhttp
+// to continue, and passes the links and attributes for the span on to
+// incomingTracer
+func trustBoundary(next http.Handler, config *config) http.Handler {
+	propagator := config.propagator
+	if propagator == nil {
+		propagator = otel.GetTextMapPropagator()
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, incoming := config.boundary.Extract(r.Context(), propagator, r)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(ctx, incomingKey{}, incoming)))
+	})
+}
+
+// incomingTracerProvider starts the otelhttp server span with the links and
+// attributes of trustBoundary, so that samplers see them
+type incomingTracerProvider struct {
+	trace.TracerProvider
+}
+
+func (p *incomingTracerProvider) Tracer(name string, opts ...trace.TracerOption) trace.Tracer {
+	return &incomingTracer{Tracer: p.TracerProvider.Tracer(name, opts...)}
+}
+
+type incomingTracer struct {
+	trace.Tracer
+}
+
+func (t *incomingTracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	if incoming, ok := ctx.Value(incomingKey{}).(inbound.Incoming); ok {
+		opts = append(opts, incoming.StartOptions()...)
+	}
+	return t.Tracer.Start(ctx, name, opts...)
+}
+
+// routePattern returns the pattern resolveRoute stored for r
+func routePattern(r *http.Request) string {
+	pattern, _ := r.Context().Value(patternKey{}).(string)
+	return pattern
+}
+
+// route strips the method and host from a ServeMux pattern
+func route(pattern string) string {
+	if _, path, ok := strings.Cut(pattern, " "); ok {
+		pattern = strings.TrimSpace(path)
+	}
+	if i := strings.Index(pattern, "/"); i > 0 {
+		pattern = pattern[i:]
+	}
+	return pattern
+}
+
+func spanName(operation string, method string, pattern string) string {
+	if pattern == "" {
+		return operation
+	}
+	return method + " " + route(pattern)
+}
@@ -0,0 +1,139 @@
+package http_server_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/weeb-vip/go-tracing-lib/logger"
+	"github.com/weeb-vip/go-tracing-lib/providers"
+	"github.com/weeb-vip/go-tracing-lib/tracing/inbound"
+	"github.com/weeb-vip/go-tracing-lib/utils/http_server"
+)
+
+func TestNewHandler(t *testing.T) {
+	t.Run("Should name spans from the mux pattern and inject the logger", func(t *testing.T) {
+		a := assert.New(t)
+		buf := &bytes.Buffer{}
+		logger.Logger(logger.WithOutput(buf))
+		defer logger.Reset()
+
+		recorder := tracetest.NewSpanRecorder()
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+			l := logger.FromContext(r.Context())
+			l.Info().Msg("loading user")
+		})
+		mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {})
+
+		handler := http_server.NewHandler(mux, "server",
+			http_server.WithTracerProvider(trace.NewTracerProvider(trace.WithSpanProcessor(recorder))),
+			http_server.WithoutPaths("/healthz"),
+			http_server.WithLogFormats(providers.LogFormatGrafana),
+		)
+
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/1", nil))
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/unknown", nil))
+
+		spans := recorder.Ended()
+		a.Len(spans, 2)
+		a.Equal("GET /users/{id}", spans[0].Name())
+		a.Contains(spans[0].Attributes(), attribute.String("http.route", "/users/{id}"))
+		a.Contains(buf.String(), `"traceID":"`+spans[0].SpanContext().TraceID().String()+`"`)
+		a.Equal("server", spans[1].Name())
+	})
+
+	t.Run("Should resolve the route of a mux behind middleware once", func(t *testing.T) {
+		a := assert.New(t)
+		recorder := tracetest.NewSpanRecorder()
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, r *http.Request) {})
+		middleware := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mux.ServeHTTP(w, r)
+		})
+
+		resolved := 0
+		handler := http_server.NewHandler(middleware, "server",
+			http_server.WithTracerProvider(trace.NewTracerProvider(trace.WithSpanProcessor(recorder))),
+			http_server.WithRouteResolver(func(r *http.Request) string {
+				resolved++
+				return http_server.MuxRoutes(mux)(r)
+			}),
+		)
+
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/1", nil))
+
+		span := recorder.Ended()[0]
+		a.Equal(1, resolved)
+		a.Equal("GET /users/{id}", span.Name())
+		a.Contains(span.Attributes(), attribute.String("http.route", "/users/{id}"))
+	})
+}
+
+// recordingSampler samples every span and keeps what it was asked about
+type recordingSampler struct {
+	params []trace.SamplingParameters
+}
+
+func (s *recordingSampler) ShouldSample(params trace.SamplingParameters) trace.SamplingResult {
+	s.params = append(s.params, params)
+	return trace.AlwaysSample().ShouldSample(params)
+}
+
+func (s *recordingSampler) Description() string { return "recording" }
+
+func TestTrustBoundary(t *testing.T) {
+	t.Run("Should restart the trace of untrusted requests", func(t *testing.T) {
+		a := assert.New(t)
+		recorder := tracetest.NewSpanRecorder()
+		handler := http_server.NewHandler(http.NotFoundHandler(), "server",
+			http_server.WithTracerProvider(trace.NewTracerProvider(trace.WithSpanProcessor(recorder))),
+			http_server.WithPropagator(propagation.TraceContext{}),
+			http_server.WithTrustBoundary(inbound.New(inbound.TrustNetworks(netip.MustParsePrefix("10.0.0.0/8")))),
+		)
+
+		for _, remoteAddr := range []string{"10.0.0.1:1234", "203.0.113.1:1234"} {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = remoteAddr
+			req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+		}
+
+		trusted, untrusted := recorder.Ended()[0], recorder.Ended()[1]
+		a.Equal("4bf92f3577b34da6a3ce929d0e0e4736", trusted.SpanContext().TraceID().String())
+		a.Contains(trusted.Attributes(), attribute.Bool("trace.inbound.trusted", true))
+		a.NotEqual("4bf92f3577b34da6a3ce929d0e0e4736", untrusted.SpanContext().TraceID().String())
+		a.False(untrusted.Parent().IsValid())
+		a.Len(untrusted.Links(), 1)
+		a.Equal("00f067aa0ba902b7", untrusted.Links()[0].SpanContext.SpanID().String())
+		a.Contains(untrusted.Attributes(), attribute.Bool("trace.inbound.trusted", false))
+	})
+
+	t.Run("Should pass the link and attributes to the sampler", func(t *testing.T) {
+		a := assert.New(t)
+		sampler := &recordingSampler{}
+		handler := http_server.NewHandler(http.NotFoundHandler(), "server",
+			http_server.WithTracerProvider(trace.NewTracerProvider(trace.WithSampler(sampler))),
+			http_server.WithPropagator(propagation.TraceContext{}),
+			http_server.WithTrustBoundary(inbound.New()),
+		)
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		a.Len(sampler.params, 1)
+		a.Len(sampler.params[0].Links, 1)
+		a.Equal("00f067aa0ba902b7", sampler.params[0].Links[0].SpanContext.SpanID().String())
+		a.Contains(sampler.params[0].Attributes, attribute.Bool("trace.inbound.trusted", false))
+	})
+}
@@ -0,0 +1,103 @@
+package http_server
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/weeb-vip/go-tracing-lib/logger"
+	"github.com/weeb-vip/go-tracing-lib/providers"
+	"github.com/weeb-vip/go-tracing-lib/tracing/inbound"
+)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	propagator     propagation.TextMapPropagator
+	filters        []otelhttp.Filter
+	logFormats     []providers.LogFormat
+	otelOptions    []otelhttp.Option
+	boundary       *inbound.Boundary
+	routeResolver  func(r *http.Request) string
+}
+
+// Option configures the handler
+type Option func(*config)
+
+// WithTracerProvider sets the tracer provider, defaults to the global provider
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = provider
+	}
+}
+
+// WithPropagator sets the propagator used to extract the incoming context,
+// defaults to the global propagator
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagator = propagator
+	}
+}
+
+// WithFilter only traces requests for which filter returns true
+func WithFilter(filter otelhttp.Filter) Option {
+	return func(c *config) {
+		c.filters = append(c.filters, filter)
+	}
+}
+
+// WithoutPaths does not trace requests to paths, e.g. health and metrics endpoints
+func WithoutPaths(paths ...string) Option {
+	ignored := make(map[string]struct{}, len(paths))
+	for _, path := range paths {
+		ignored[path] = struct{}{}
+	}
+	return WithFilter(func(r *http.Request) bool {
+		_, ok := ignored[r.URL.Path]
+		return !ok
+	})
+}
+
+// WithLogFormats selects the trace identifier formats of the request logger,
+// defaults to logger.DefaultTraceFormats
+func WithLogFormats(formats ...providers.LogFormat) Option {
+	return func(c *config) {
+		c.logFormats = formats
+	}
+}
+
+// WithTrustBoundary only continues the trace of requests the boundary trusts,
+// untrusted requests start a new trace
+func WithTrustBoundary(boundary *inbound.Boundary) Option {
+	return func(c *config) {
+		c.boundary = boundary
+	}
+}
+
+// WithRouteResolver sets how the route of a request is found for span names
+// and http.route, e.g. MuxRoutes(mux) when the ServeMux sits behind other
+// handlers. It returns a ServeMux pattern or a route, or "" when unknown.
+// Defaults to MuxRoutes when NewHandler wraps a *http.ServeMux.
+func WithRouteResolver(resolver func(r *http.Request) string) Option {
+	return func(c *config) {
+		c.routeResolver = resolver
+	}
+}
+
+// WithOtelOptions passes additional options to otelhttp
+func WithOtelOptions(opts ...otelhttp.Option) Option {
+	return func(c *config) {
+		c.otelOptions = append(c.otelOptions, opts...)
+	}
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{
+		logFormats: logger.DefaultTraceFormats,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
@@ -0,0 +1,135 @@
+package kafka
+
+import (
+	"github.com/IBM/sarama"
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+var (
+	_ propagation.TextMapCarrier = &MessageCarrier{}
+	_ propagation.TextMapCarrier = &ProducerMessageCarrier{}
+	_ propagation.TextMapCarrier = &ConsumerMessageCarrier{}
+)
+
+// MessageCarrier adapts the headers of a kafka-go message to a propagation.TextMapCarrier
+type MessageCarrier struct {
+	msg *kafka.Message
+}
+
+// NewMessageCarrier returns a carrier over the headers of msg
+func NewMessageCarrier(msg *kafka.Message) *MessageCarrier {
+	return &MessageCarrier{msg: msg}
+}
+
+// Get returns the value of the header key
+func (c *MessageCarrier) Get(key string) string {
+	for _, h := range c.msg.Headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+// Set replaces the header key, or appends it
+func (c *MessageCarrier) Set(key string, value string) {
+	for i, h := range c.msg.Headers {
+		if h.Key == key {
+			c.msg.Headers[i].Value = []byte(value)
+			return
+		}
+	}
+	c.msg.Headers = append(c.msg.Headers, kafka.Header{Key: key, Value: []byte(value)})
+}
+
+// Keys returns the keys of the headers
+func (c *MessageCarrier) Keys() []string {
+	keys := make([]string, len(c.msg.Headers))
+	for i, h := range c.msg.Headers {
+		keys[i] = h.Key
+	}
+	return keys
+}
+
+// ProducerMessageCarrier adapts the headers of a sarama producer message to a propagation.TextMapCarrier
+type ProducerMessageCarrier struct {
+	msg *sarama.ProducerMessage
+}
+
+// NewProducerMessageCarrier returns a carrier over the headers of msg
+func NewProducerMessageCarrier(msg *sarama.ProducerMessage) *ProducerMessageCarrier {
+	return &ProducerMessageCarrier{msg: msg}
+}
+
+// Get returns the value of the header key
+func (c *ProducerMessageCarrier) Get(key string) string {
+	for _, h := range c.msg.Headers {
+		if string(h.Key) == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+// Set replaces the header key, or appends it
+func (c *ProducerMessageCarrier) Set(key string, value string) {
+	for i, h := range c.msg.Headers {
+		if string(h.Key) == key {
+			c.msg.Headers[i].Value = []byte(value)
+			return
+		}
+	}
+	c.msg.Headers = append(c.msg.Headers, sarama.RecordHeader{Key: []byte(key), Value: []byte(value)})
+}
+
+// Keys returns the keys of the headers
+func (c *ProducerMessageCarrier) Keys() []string {
+	keys := make([]string, len(c.msg.Headers))
+	for i, h := range c.msg.Headers {
+		keys[i] = string(h.Key)
+	}
+	return keys
+}
+
+// ConsumerMessageCarrier adapts the headers of a sarama consumer message to a propagation.TextMapCarrier
+type ConsumerMessageCarrier struct {
+	msg *sarama.ConsumerMessage
+}
+
+// NewConsumerMessageCarrier returns a carrier over the headers of msg
+func NewConsumerMessageCarrier(msg *sarama.ConsumerMessage) *ConsumerMessageCarrier {
+	return &ConsumerMessageCarrier{msg: msg}
+}
+
+// Get returns the value of the header key
+func (c *ConsumerMessageCarrier) Get(key string) string {
+	for _, h := range c.msg.Headers {
+		if h != nil && string(h.Key) == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+// Set replaces the header key, or appends it
+func (c *ConsumerMessageCarrier) Set(key string, value string) {
+	for _, h := range c.msg.Headers {
+		if h != nil && string(h.Key) == key {
+			h.Value = []byte(value)
+			return
+		}
+	}
+	c.msg.Headers = append(c.msg.Headers, &sarama.RecordHeader{Key: []byte(key), Value: []byte(value)})
+}
+
+// Keys returns the keys of the headers
+func (c *ConsumerMessageCarrier) Keys() []string {
+	keys := make([]string, 0, len(c.msg.Headers))
+	for _, h := range c.msg.Headers {
+		if h != nil {
+			keys = append(keys, string(h.Key))
+		}
+	}
+	return keys
+}
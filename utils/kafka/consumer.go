@@ -0,0 +1,100 @@
+package kafka
+
+import (
+	"context"
+
+	"github.com/IBM/sarama"
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/weeb-vip/go-tracing-lib/logger"
+)
+
+// ExtractTraceContext extracts trace context from a kafka-go message
+func ExtractTraceContext(ctx context.Context, msg kafka.Message) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, NewMessageCarrier(&msg))
+}
+
+// ExtractTraceContextFromConsumerMessage extracts trace context from a sarama message
+func ExtractTraceContextFromConsumerMessage(ctx context.Context, msg *sarama.ConsumerMessage) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, NewConsumerMessageCarrier(msg))
+}
+
+// MessageHandler processes a kafka-go message within its consumer span
+type MessageHandler func(ctx context.Context, msg kafka.Message) error
+
+// ConsumerMessageHandler processes a sarama message within its consumer span
+type ConsumerMessageHandler func(ctx context.Context, msg *sarama.ConsumerMessage) error
+
+// Consumer processes the messages of a consumer group in consumer spans
+type Consumer struct {
+	group  string
+	config *config
+}
+
+// NewConsumer creates a consumer for messages read by group, which may be
+// empty when reading without a consumer group
+func NewConsumer(group string, opts ...Option) *Consumer {
+	return &Consumer{group: group, config: newConfig(opts)}
+}
+
+// Handle runs handler for a kafka-go message in a consumer span parented to,
+// or linked with, the producer's span. The context carries the trace-aware logger.
+func (c *Consumer) Handle(ctx context.Context, msg kafka.Message, handler MessageHandler) error {
+	ctx, span := c.start(ctx, NewMessageCarrier(&msg), msg.Topic, msg.Partition, msg.Offset, msg.Key, len(msg.Value))
+	defer span.End()
+	return c.finish(span, handler(ctx, msg))
+}
+
+// HandleConsumerMessage runs handler for a sarama message like Handle
+func (c *Consumer) HandleConsumerMessage(ctx context.Context, msg *sarama.ConsumerMessage, handler ConsumerMessageHandler) error {
+	ctx, span := c.start(ctx, NewConsumerMessageCarrier(msg), msg.Topic, int(msg.Partition), msg.Offset, msg.Key, len(msg.Value))
+	defer span.End()
+	return c.finish(span, handler(ctx, msg))
+}
+
+func (c *Consumer) start(ctx context.Context, carrier propagation.TextMapCarrier, topic string, partition int, offset int64, key []byte, size int) (context.Context, trace.Span) {
+	attrs := []attribute.KeyValue{
+		semconv.MessagingSystem("kafka"),
+		semconv.MessagingOperationProcess,
+		semconv.MessagingSourceName(topic),
+		semconv.MessagingKafkaSourcePartition(partition),
+		semconv.MessagingKafkaMessageOffset(int(offset)),
+		semconv.MessagingMessagePayloadSizeBytes(size),
+	}
+	if c.group != "" {
+		attrs = append(attrs, semconv.MessagingKafkaConsumerGroup(c.group))
+	}
+	if len(key) > 0 {
+		attrs = append(attrs, semconv.MessagingKafkaMessageKey(string(key)))
+	}
+	startOpts := []trace.SpanStartOption{
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(attrs...),
+	}
+
+	producerCtx := c.config.propagator.Extract(ctx, carrier)
+	if c.config.linkToProducer {
+		if producer := trace.SpanContextFromContext(producerCtx); producer.IsValid() {
+			startOpts = append(startOpts, trace.WithLinks(trace.Link{SpanContext: producer}))
+		}
+	} else {
+		ctx = producerCtx
+	}
+
+	ctx, span := c.config.tracer().Start(ctx, topic+" process", startOpts...)
+	return logger.ContextWithTraceFormats(ctx, c.config.logFormats...), span
+}
+
+func (c *Consumer) finish(span trace.Span, err error) error {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
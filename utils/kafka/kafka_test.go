@@ -0,0 +1,157 @@
+package kafka_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	kafkago "github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+
+	"github.com/weeb-vip/go-tracing-lib/utils/kafka"
+)
+
+type fakeWriter struct {
+	written []kafkago.Message
+	err     error
+}
+
+func (w *fakeWriter) WriteMessages(_ context.Context, msgs ...kafkago.Message) error {
+	w.written = append(w.written, msgs...)
+	return w.err
+}
+
+func setup() ([]kafka.Option, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	return []kafka.Option{
+		kafka.WithTracerProvider(trace.NewTracerProvider(trace.WithSpanProcessor(recorder))),
+		kafka.WithPropagator(propagation.TraceContext{}),
+	}, recorder
+}
+
+func TestCarrier(t *testing.T) {
+	t.Run("Should replace existing headers", func(t *testing.T) {
+		a := assert.New(t)
+		msg := kafkago.Message{Headers: []kafkago.Header{{Key: "traceparent", Value: []byte("old")}}}
+
+		carrier := kafka.NewMessageCarrier(&msg)
+		carrier.Set("traceparent", "new")
+		carrier.Set("tracestate", "state")
+
+		a.Equal("new", carrier.Get("traceparent"))
+		a.Equal([]string{"traceparent", "tracestate"}, carrier.Keys())
+	})
+}
+
+func TestWriter(t *testing.T) {
+	t.Run("Should write kafka-go messages in producer spans", func(t *testing.T) {
+		a := assert.New(t)
+		opts, recorder := setup()
+		writer := &fakeWriter{}
+
+		err := kafka.NewWriter(writer, opts...).WriteMessages(context.Background(),
+			kafkago.Message{Topic: "users", Key: []byte("42"), Value: []byte("created")})
+
+		a.NoError(err)
+		span := recorder.Ended()[0]
+		a.Equal("users publish", span.Name())
+		a.Equal(oteltrace.SpanKindProducer, span.SpanKind())
+		a.Contains(span.Attributes(), attribute.String("messaging.system", "kafka"))
+		a.Contains(span.Attributes(), attribute.String("messaging.kafka.message.key", "42"))
+		a.Contains(span.Attributes(), attribute.Int("messaging.message.payload_size_bytes", 7))
+		a.Contains(string(writer.written[0].Headers[0].Value), span.SpanContext().SpanID().String())
+	})
+
+	t.Run("Should record write errors", func(t *testing.T) {
+		a := assert.New(t)
+		opts, recorder := setup()
+		writer := &fakeWriter{err: errors.New("broker down")}
+
+		err := kafka.NewWriter(writer, opts...).WriteMessages(context.Background(), kafkago.Message{Topic: "users"})
+
+		a.Error(err)
+		a.Equal(codes.Error, recorder.Ended()[0].Status().Code)
+	})
+}
+
+func TestSyncProducer(t *testing.T) {
+	t.Run("Should send sarama messages with partition and offset", func(t *testing.T) {
+		a := assert.New(t)
+		opts, recorder := setup()
+		mock := mocks.NewSyncProducer(t, nil)
+		mock.ExpectSendMessageAndSucceed()
+		mock.ExpectSendMessageAndSucceed()
+		producer := kafka.NewSyncProducer(mock, opts...)
+
+		msg := &sarama.ProducerMessage{Topic: "users", Value: sarama.StringEncoder("created")}
+		_, _, err := producer.SendMessage(context.Background(), msg)
+		a.NoError(err)
+		partition, offset, err := producer.SendMessage(context.Background(), &sarama.ProducerMessage{Topic: "users"})
+		a.NoError(err)
+		a.NoError(producer.Close())
+
+		spans := recorder.Ended()
+		a.Len(spans, 2)
+		a.Contains(spans[1].Attributes(), attribute.Int("messaging.kafka.destination.partition", int(partition)))
+		a.Contains(spans[1].Attributes(), attribute.Int("messaging.kafka.message.offset", int(offset)))
+		a.Equal("traceparent", string(msg.Headers[0].Key))
+	})
+}
+
+func TestConsumer(t *testing.T) {
+	t.Run("Should process kafka-go messages in a child of the producer span", func(t *testing.T) {
+		a := assert.New(t)
+		opts, recorder := setup()
+		writer := &fakeWriter{}
+		a.NoError(kafka.NewWriter(writer, opts...).WriteMessages(context.Background(), kafkago.Message{Topic: "users"}))
+		msg := writer.written[0]
+		msg.Partition, msg.Offset = 3, 17
+
+		err := kafka.NewConsumer("workers", opts...).Handle(context.Background(), msg, func(ctx context.Context, msg kafkago.Message) error {
+			return nil
+		})
+
+		a.NoError(err)
+		producer, consumer := recorder.Ended()[0], recorder.Ended()[1]
+		a.Equal("users process", consumer.Name())
+		a.Equal(oteltrace.SpanKindConsumer, consumer.SpanKind())
+		a.Equal(producer.SpanContext().SpanID(), consumer.Parent().SpanID())
+		a.Contains(consumer.Attributes(), attribute.Int("messaging.kafka.source.partition", 3))
+		a.Contains(consumer.Attributes(), attribute.Int("messaging.kafka.message.offset", 17))
+		a.Contains(consumer.Attributes(), attribute.String("messaging.kafka.consumer.group", "workers"))
+	})
+
+	t.Run("Should link sarama messages to the producer span and record errors", func(t *testing.T) {
+		a := assert.New(t)
+		opts, recorder := setup()
+		mock := mocks.NewSyncProducer(t, nil)
+		mock.ExpectSendMessageAndSucceed()
+		sent := &sarama.ProducerMessage{Topic: "users"}
+		_, _, err := kafka.NewSyncProducer(mock, opts...).SendMessage(context.Background(), sent)
+		a.NoError(err)
+
+		msg := &sarama.ConsumerMessage{Topic: "users"}
+		for _, h := range sent.Headers {
+			msg.Headers = append(msg.Headers, &sarama.RecordHeader{Key: h.Key, Value: h.Value})
+		}
+		failure := errors.New("boom")
+		consumer := kafka.NewConsumer("workers", append(opts, kafka.WithLinkToProducer())...)
+		err = consumer.HandleConsumerMessage(context.Background(), msg, func(ctx context.Context, msg *sarama.ConsumerMessage) error {
+			return failure
+		})
+
+		a.ErrorIs(err, failure)
+		producer, span := recorder.Ended()[0], recorder.Ended()[1]
+		a.False(span.Parent().IsValid())
+		a.Equal(producer.SpanContext().SpanID(), span.Links()[0].SpanContext.SpanID())
+		a.Equal(codes.Error, span.Status().Code)
+	})
+}
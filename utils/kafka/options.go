@@ -0,0 +1,68 @@
+package kafka
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/weeb-vip/go-tracing-lib/logger"
+	"github.com/weeb-vip/go-tracing-lib/providers"
+)
+
+const instrumentationName = "github.com/weeb-vip/go-tracing-lib/utils/kafka"
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	propagator     propagation.TextMapPropagator
+	logFormats     []providers.LogFormat
+	linkToProducer bool
+}
+
+// Option configures the traced producers and consumer
+type Option func(*config)
+
+// WithTracerProvider sets the tracer provider, defaults to the global provider
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = provider
+	}
+}
+
+// WithPropagator sets the propagator used for record headers, defaults to the global propagator
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagator = propagator
+	}
+}
+
+// WithLogFormats selects the trace identifier formats of the consumer logger,
+// defaults to logger.DefaultTraceFormats
+func WithLogFormats(formats ...providers.LogFormat) Option {
+	return func(c *config) {
+		c.logFormats = formats
+	}
+}
+
+// WithLinkToProducer starts consumer spans in a new trace linked to the
+// producer span, instead of continuing the producer's trace
+func WithLinkToProducer() Option {
+	return func(c *config) {
+		c.linkToProducer = true
+	}
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{
+		tracerProvider: otel.GetTracerProvider(),
+		propagator:     otel.GetTextMapPropagator(),
+		logFormats:     logger.DefaultTraceFormats,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *config) tracer() trace.Tracer {
+	return c.tracerProvider.Tracer(instrumentationName)
+}
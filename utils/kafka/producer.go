@@ -0,0 +1,170 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+
+	"github.com/IBM/sarama"
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WrapMessage adds the trace context of ctx to the headers of a kafka-go message
+func WrapMessage(ctx context.Context, msg kafka.Message) kafka.Message {
+	msg.Headers = append([]kafka.Header(nil), msg.Headers...)
+	otel.GetTextMapPropagator().Inject(ctx, NewMessageCarrier(&msg))
+	return msg
+}
+
+// WrapProducerMessage adds the trace context of ctx to the headers of a sarama message
+func WrapProducerMessage(ctx context.Context, msg *sarama.ProducerMessage) *sarama.ProducerMessage {
+	otel.GetTextMapPropagator().Inject(ctx, NewProducerMessageCarrier(msg))
+	return msg
+}
+
+// MessageWriter is the writing side of *kafka.Writer
+type MessageWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+}
+
+// Writer writes kafka-go messages in a producer span per message and carries the span in the headers
+type Writer struct {
+	writer MessageWriter
+	config *config
+}
+
+// NewWriter creates a writer over writer, usually a *kafka.Writer
+func NewWriter(writer MessageWriter, opts ...Option) *Writer {
+	return &Writer{writer: writer, config: newConfig(opts)}
+}
+
+// WriteMessages writes msgs like kafka.Writer.WriteMessages
+func (w *Writer) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	defaultTopic := ""
+	if writer, ok := w.writer.(*kafka.Writer); ok {
+		defaultTopic = writer.Topic
+	}
+
+	spans := make([]trace.Span, len(msgs))
+	traced := make([]kafka.Message, len(msgs))
+	for i, msg := range msgs {
+		topic := msg.Topic
+		if topic == "" {
+			topic = defaultTopic
+		}
+		var spanCtx context.Context
+		spanCtx, spans[i] = startProducerSpan(ctx, w.config, topic, msg.Key, len(msg.Value))
+
+		msg.Headers = append([]kafka.Header(nil), msg.Headers...)
+		w.config.propagator.Inject(spanCtx, NewMessageCarrier(&msg))
+		traced[i] = msg
+	}
+
+	err := w.writer.WriteMessages(ctx, traced...)
+	var writeErrors kafka.WriteErrors
+	for i, span := range spans {
+		msgErr := err
+		if errors.As(err, &writeErrors) && len(writeErrors) == len(spans) {
+			msgErr = writeErrors[i]
+		}
+		finishProducerSpan(span, msgErr)
+	}
+	return err
+}
+
+// SyncProducer sends sarama messages in a producer span per message and carries the span in the headers
+type SyncProducer struct {
+	producer sarama.SyncProducer
+	config   *config
+}
+
+// NewSyncProducer creates a producer over a sarama.SyncProducer
+func NewSyncProducer(producer sarama.SyncProducer, opts ...Option) *SyncProducer {
+	return &SyncProducer{producer: producer, config: newConfig(opts)}
+}
+
+// SendMessage sends msg like sarama.SyncProducer.SendMessage and records where it was stored
+func (p *SyncProducer) SendMessage(ctx context.Context, msg *sarama.ProducerMessage) (int32, int64, error) {
+	span := p.start(ctx, msg)
+	partition, offset, err := p.producer.SendMessage(msg)
+	if err == nil {
+		span.SetAttributes(semconv.MessagingKafkaDestinationPartition(int(partition)), semconv.MessagingKafkaMessageOffset(int(offset)))
+	}
+	finishProducerSpan(span, err)
+	return partition, offset, err
+}
+
+// SendMessages sends msgs like sarama.SyncProducer.SendMessages
+func (p *SyncProducer) SendMessages(ctx context.Context, msgs []*sarama.ProducerMessage) error {
+	spans := make([]trace.Span, len(msgs))
+	for i, msg := range msgs {
+		spans[i] = p.start(ctx, msg)
+	}
+
+	err := p.producer.SendMessages(msgs)
+	failed := map[*sarama.ProducerMessage]error{}
+	var producerErrors sarama.ProducerErrors
+	if errors.As(err, &producerErrors) {
+		for _, producerErr := range producerErrors {
+			failed[producerErr.Msg] = producerErr.Err
+		}
+	}
+	for i, span := range spans {
+		msgErr, ok := failed[msgs[i]]
+		if !ok && len(failed) == 0 {
+			msgErr = err
+		}
+		if msgErr == nil {
+			span.SetAttributes(semconv.MessagingKafkaDestinationPartition(int(msgs[i].Partition)), semconv.MessagingKafkaMessageOffset(int(msgs[i].Offset)))
+		}
+		finishProducerSpan(span, msgErr)
+	}
+	return err
+}
+
+// Close closes the underlying producer
+func (p *SyncProducer) Close() error {
+	return p.producer.Close()
+}
+
+func (p *SyncProducer) start(ctx context.Context, msg *sarama.ProducerMessage) trace.Span {
+	var key []byte
+	if msg.Key != nil {
+		key, _ = msg.Key.Encode()
+	}
+	size := 0
+	if msg.Value != nil {
+		size = msg.Value.Length()
+	}
+	ctx, span := startProducerSpan(ctx, p.config, msg.Topic, key, size)
+	p.config.propagator.Inject(ctx, NewProducerMessageCarrier(msg))
+	return span
+}
+
+func startProducerSpan(ctx context.Context, c *config, topic string, key []byte, size int) (context.Context, trace.Span) {
+	attrs := []attribute.KeyValue{
+		semconv.MessagingSystem("kafka"),
+		semconv.MessagingOperationPublish,
+		semconv.MessagingDestinationName(topic),
+		semconv.MessagingMessagePayloadSizeBytes(size),
+	}
+	if len(key) > 0 {
+		attrs = append(attrs, semconv.MessagingKafkaMessageKey(string(key)))
+	}
+	return c.tracer().Start(ctx, topic+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(attrs...),
+	)
+}
+
+func finishProducerSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,48 @@
+package nats
+
+import (
+	"context"
+
+	"github.com/nats-io/nats.go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// HeaderCarrier adapts nats.Header to a propagation.TextMapCarrier
+type HeaderCarrier nats.Header
+
+var _ propagation.TextMapCarrier = HeaderCarrier{}
+
+func (h HeaderCarrier) Get(key string) string {
+	return nats.Header(h).Get(key)
+}
+
+func (h HeaderCarrier) Set(key string, value string) {
+	nats.Header(h).Set(key, value)
+}
+
+func (h HeaderCarrier) Keys() []string {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// WrapMsg adds the trace context of ctx to the message headers
+func WrapMsg(ctx context.Context, msg *nats.Msg) *nats.Msg {
+	return injectHeaders(ctx, otel.GetTextMapPropagator(), msg)
+}
+
+// ExtractTraceContext extracts trace context from the message headers
+func ExtractTraceContext(ctx context.Context, msg *nats.Msg) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, HeaderCarrier(msg.Header))
+}
+
+func injectHeaders(ctx context.Context, propagator propagation.TextMapPropagator, msg *nats.Msg) *nats.Msg {
+	if msg.Header == nil {
+		msg.Header = nats.Header{}
+	}
+	propagator.Inject(ctx, HeaderCarrier(msg.Header))
+	return msg
+}
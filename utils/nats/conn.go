@@ -0,0 +1,149 @@
+package nats
+
+import (
+	"context"
+
+	"github.com/nats-io/nats.go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/weeb-vip/go-tracing-lib/logger"
+)
+
+const (
+	MessageIDHeader = "Nats-Msg-Id"
+
+	QueueGroupKey = attribute.Key("messaging.nats.queue_group")
+)
+
+// MsgHandler processes a message within its consumer span
+type MsgHandler func(ctx context.Context, msg *nats.Msg) error
+
+// Conn publishes, requests and subscribes over a *nats.Conn in producer,
+// client and consumer spans, carrying the trace context in the message headers
+type Conn struct {
+	conn   *nats.Conn
+	config *config
+}
+
+// NewConn creates a traced connection over conn
+func NewConn(conn *nats.Conn, opts ...Option) *Conn {
+	return &Conn{conn: conn, config: newConfig(opts)}
+}
+
+// Publish publishes data to subject
+func (c *Conn) Publish(ctx context.Context, subject string, data []byte) error {
+	return c.PublishMsg(ctx, &nats.Msg{Subject: subject, Data: data})
+}
+
+// PublishMsg publishes msg in a producer span
+func (c *Conn) PublishMsg(ctx context.Context, msg *nats.Msg) error {
+	ctx, span := startProducerSpan(ctx, c.config, msg.Subject+" publish", msg, trace.SpanKindProducer)
+	defer span.End()
+
+	injectHeaders(ctx, c.config.propagator, msg)
+	return finish(span, c.conn.PublishMsg(msg))
+}
+
+// Request sends data to subject and waits for the reply until ctx is done
+func (c *Conn) Request(ctx context.Context, subject string, data []byte) (*nats.Msg, error) {
+	return c.RequestMsg(ctx, &nats.Msg{Subject: subject, Data: data})
+}
+
+// RequestMsg sends msg in a client span and waits for the reply until ctx is done
+func (c *Conn) RequestMsg(ctx context.Context, msg *nats.Msg) (*nats.Msg, error) {
+	ctx, span := startProducerSpan(ctx, c.config, msg.Subject+" request", msg, trace.SpanKindClient)
+	defer span.End()
+
+	injectHeaders(ctx, c.config.propagator, msg)
+	reply, err := c.conn.RequestMsgWithContext(ctx, msg)
+	return reply, finish(span, err)
+}
+
+// Respond replies to request in a producer span, ctx is usually the one given to the handler
+func (c *Conn) Respond(ctx context.Context, request *nats.Msg, data []byte) error {
+	msg := &nats.Msg{Subject: request.Reply, Data: data}
+	ctx, span := startProducerSpan(ctx, c.config, request.Subject+" reply", msg, trace.SpanKindProducer)
+	defer span.End()
+	span.SetAttributes(semconv.MessagingDestinationTemporary(true))
+
+	injectHeaders(ctx, c.config.propagator, msg)
+	return finish(span, c.conn.PublishMsg(msg))
+}
+
+// Subscribe subscribes handler to subject
+func (c *Conn) Subscribe(subject string, handler MsgHandler) (*nats.Subscription, error) {
+	return c.conn.Subscribe(subject, c.Handler(handler))
+}
+
+// QueueSubscribe subscribes handler to subject as a member of queue
+func (c *Conn) QueueSubscribe(subject, queue string, handler MsgHandler) (*nats.Subscription, error) {
+	return c.conn.QueueSubscribe(subject, queue, c.Handler(handler))
+}
+
+// Handler adapts handler to a nats.MsgHandler running it in a consumer span
+// parented to, or linked with, the publisher's span. The context carries the
+// trace-aware logger.
+func (c *Conn) Handler(handler MsgHandler) nats.MsgHandler {
+	return func(msg *nats.Msg) {
+		var attrs []attribute.KeyValue
+		if msg.Sub != nil && msg.Sub.Queue != "" {
+			attrs = append(attrs, QueueGroupKey.String(msg.Sub.Queue))
+		}
+		ctx, span := startConsumerSpan(context.Background(), c.config, msg.Subject, msg.Header, len(msg.Data), attrs...)
+		defer span.End()
+
+		_ = finish(span, handler(ctx, msg))
+	}
+}
+
+func startProducerSpan(ctx context.Context, c *config, name string, msg *nats.Msg, kind trace.SpanKind) (context.Context, trace.Span) {
+	attrs := []attribute.KeyValue{
+		semconv.MessagingSystem("nats"),
+		semconv.MessagingOperationPublish,
+		semconv.MessagingDestinationName(msg.Subject),
+		semconv.MessagingMessagePayloadSizeBytes(len(msg.Data)),
+	}
+	if id := msg.Header.Get(MessageIDHeader); id != "" {
+		attrs = append(attrs, semconv.MessagingMessageID(id))
+	}
+	return c.tracer().Start(ctx, name, trace.WithSpanKind(kind), trace.WithAttributes(attrs...))
+}
+
+func startConsumerSpan(ctx context.Context, c *config, subject string, header nats.Header, size int, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append([]attribute.KeyValue{
+		semconv.MessagingSystem("nats"),
+		semconv.MessagingOperationProcess,
+		semconv.MessagingSourceName(subject),
+		semconv.MessagingMessagePayloadSizeBytes(size),
+	}, attrs...)
+	if id := header.Get(MessageIDHeader); id != "" {
+		attrs = append(attrs, semconv.MessagingMessageID(id))
+	}
+	startOpts := []trace.SpanStartOption{
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(attrs...),
+	}
+
+	producerCtx := c.propagator.Extract(ctx, HeaderCarrier(header))
+	if c.linkToProducer {
+		if producer := trace.SpanContextFromContext(producerCtx); producer.IsValid() {
+			startOpts = append(startOpts, trace.WithLinks(trace.Link{SpanContext: producer}))
+		}
+	} else {
+		ctx = producerCtx
+	}
+
+	ctx, span := c.tracer().Start(ctx, subject+" process", startOpts...)
+	return logger.ContextWithTraceFormats(ctx, c.logFormats...), span
+}
+
+func finish(span trace.Span, err error) error {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
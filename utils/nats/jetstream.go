@@ -0,0 +1,129 @@
+package nats
+
+import (
+	"context"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	StreamKey         = attribute.Key("messaging.nats.stream")
+	ConsumerKey       = attribute.Key("messaging.nats.consumer")
+	StreamSequenceKey = attribute.Key("messaging.nats.stream_sequence")
+	NumDeliveredKey   = attribute.Key("messaging.nats.num_delivered")
+	DuplicateKey      = attribute.Key("messaging.nats.duplicate")
+	OutcomeKey        = attribute.Key("messaging.nats.outcome")
+)
+
+// JetStreamHandler processes a JetStream message within its consumer span
+type JetStreamHandler func(ctx context.Context, msg jetstream.Msg) error
+
+// JetStream publishes and consumes JetStream messages in producer and
+// consumer spans, carrying the trace context in the message headers
+type JetStream struct {
+	js     jetstream.JetStream
+	config *config
+}
+
+// NewJetStream creates a traced JetStream over js
+func NewJetStream(js jetstream.JetStream, opts ...Option) *JetStream {
+	return &JetStream{js: js, config: newConfig(opts)}
+}
+
+// Publish publishes data to subject and waits for the stream to acknowledge it
+func (j *JetStream) Publish(ctx context.Context, subject string, data []byte, opts ...jetstream.PublishOpt) (*jetstream.PubAck, error) {
+	return j.PublishMsg(ctx, &nats.Msg{Subject: subject, Data: data}, opts...)
+}
+
+// PublishMsg publishes msg in a producer span recording the stream and sequence it was stored at
+func (j *JetStream) PublishMsg(ctx context.Context, msg *nats.Msg, opts ...jetstream.PublishOpt) (*jetstream.PubAck, error) {
+	ctx, span := startProducerSpan(ctx, j.config, msg.Subject+" publish", msg, trace.SpanKindProducer)
+	defer span.End()
+
+	injectHeaders(ctx, j.config.propagator, msg)
+	ack, err := j.js.PublishMsg(ctx, msg, opts...)
+	if err != nil {
+		return nil, finish(span, err)
+	}
+	span.SetAttributes(
+		StreamKey.String(ack.Stream),
+		StreamSequenceKey.Int64(int64(ack.Sequence)),
+		DuplicateKey.Bool(ack.Duplicate),
+	)
+	return ack, nil
+}
+
+// Handler adapts handler to a jetstream.MessageHandler, e.g. for
+// jetstream.Consumer.Consume, running it in a consumer span like Conn.Handler.
+// Acks, naks and terms made through the message are recorded on the span.
+func (j *JetStream) Handler(handler JetStreamHandler) jetstream.MessageHandler {
+	return func(msg jetstream.Msg) {
+		var attrs []attribute.KeyValue
+		if metadata, err := msg.Metadata(); err == nil {
+			attrs = append(attrs,
+				StreamKey.String(metadata.Stream),
+				ConsumerKey.String(metadata.Consumer),
+				StreamSequenceKey.Int64(int64(metadata.Sequence.Stream)),
+				NumDeliveredKey.Int64(int64(metadata.NumDelivered)),
+			)
+		}
+		ctx, span := startConsumerSpan(context.Background(), j.config, msg.Subject(), msg.Headers(), len(msg.Data()), attrs...)
+		defer span.End()
+
+		traced := &tracedMsg{Msg: msg, span: span}
+		err := finish(span, handler(ctx, traced))
+		if !j.config.autoAck || traced.acknowledged {
+			return
+		}
+		if err == nil {
+			_ = traced.Ack()
+		} else {
+			_ = traced.Nak()
+		}
+	}
+}
+
+// tracedMsg records the outcome of a JetStream message on its consumer span
+type tracedMsg struct {
+	jetstream.Msg
+	span         trace.Span
+	acknowledged bool
+}
+
+func (m *tracedMsg) Ack() error {
+	return m.record("ack", m.Msg.Ack())
+}
+
+func (m *tracedMsg) DoubleAck(ctx context.Context) error {
+	return m.record("ack", m.Msg.DoubleAck(ctx))
+}
+
+func (m *tracedMsg) Nak() error {
+	return m.record("nak", m.Msg.Nak())
+}
+
+func (m *tracedMsg) NakWithDelay(delay time.Duration) error {
+	return m.record("nak", m.Msg.NakWithDelay(delay))
+}
+
+func (m *tracedMsg) Term() error {
+	return m.record("term", m.Msg.Term())
+}
+
+func (m *tracedMsg) TermWithReason(reason string) error {
+	return m.record("term", m.Msg.TermWithReason(reason))
+}
+
+func (m *tracedMsg) record(outcome string, err error) error {
+	m.acknowledged = true
+	m.span.SetAttributes(OutcomeKey.String(outcome))
+	m.span.AddEvent(outcome)
+	if err != nil {
+		m.span.RecordError(err)
+	}
+	return err
+}
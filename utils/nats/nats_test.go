@@ -0,0 +1,150 @@
+package nats_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+
+	tracing_nats "github.com/weeb-vip/go-tracing-lib/utils/nats"
+)
+
+func setup(t *testing.T) (*nats.Conn, []tracing_nats.Option, *tracetest.SpanRecorder) {
+	srv, err := server.NewServer(&server.Options{Port: -1, JetStream: true, StoreDir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go srv.Start()
+	if !srv.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats server not ready")
+	}
+	t.Cleanup(srv.Shutdown)
+
+	conn, err := nats.Connect(srv.ClientURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(conn.Close)
+
+	recorder := tracetest.NewSpanRecorder()
+	return conn, []tracing_nats.Option{
+		tracing_nats.WithTracerProvider(trace.NewTracerProvider(trace.WithSpanProcessor(recorder))),
+		tracing_nats.WithPropagator(propagation.TraceContext{}),
+	}, recorder
+}
+
+func spanNamed(recorder *tracetest.SpanRecorder, name string) trace.ReadOnlySpan {
+	for _, span := range recorder.Ended() {
+		if span.Name() == name {
+			return span
+		}
+	}
+	return nil
+}
+
+func TestConn(t *testing.T) {
+	t.Run("Should continue the publisher's trace in subscribers", func(t *testing.T) {
+		a := assert.New(t)
+		conn, opts, recorder := setup(t)
+		traced := tracing_nats.NewConn(conn, opts...)
+
+		done := make(chan struct{})
+		_, err := traced.QueueSubscribe("users.created", "workers", func(ctx context.Context, msg *nats.Msg) error {
+			defer close(done)
+			return errors.New("boom")
+		})
+		a.NoError(err)
+
+		a.NoError(traced.Publish(context.Background(), "users.created", []byte("42")))
+		<-done
+		time.Sleep(10 * time.Millisecond)
+
+		producer := spanNamed(recorder, "users.created publish")
+		consumer := spanNamed(recorder, "users.created process")
+		a.Equal(oteltrace.SpanKindProducer, producer.SpanKind())
+		a.Contains(producer.Attributes(), attribute.String("messaging.system", "nats"))
+		a.Equal(oteltrace.SpanKindConsumer, consumer.SpanKind())
+		a.Equal(producer.SpanContext().SpanID(), consumer.Parent().SpanID())
+		a.Contains(consumer.Attributes(), attribute.String("messaging.nats.queue_group", "workers"))
+		a.Equal(codes.Error, consumer.Status().Code)
+	})
+
+	t.Run("Should trace request and reply", func(t *testing.T) {
+		a := assert.New(t)
+		conn, opts, recorder := setup(t)
+		traced := tracing_nats.NewConn(conn, opts...)
+
+		_, err := traced.Subscribe("users.get", func(ctx context.Context, msg *nats.Msg) error {
+			return traced.Respond(ctx, msg, []byte("alice"))
+		})
+		a.NoError(err)
+
+		reply, err := traced.Request(context.Background(), "users.get", []byte("42"))
+		a.NoError(err)
+		a.Equal("alice", string(reply.Data))
+		time.Sleep(10 * time.Millisecond)
+
+		request := spanNamed(recorder, "users.get request")
+		process := spanNamed(recorder, "users.get process")
+		response := spanNamed(recorder, "users.get reply")
+		a.Equal(oteltrace.SpanKindClient, request.SpanKind())
+		a.Equal(request.SpanContext().SpanID(), process.Parent().SpanID())
+		a.Equal(process.SpanContext().SpanID(), response.Parent().SpanID())
+		a.Contains(response.Attributes(), attribute.Bool("messaging.destination.temporary", true))
+		a.Contains(reply.Header.Get("traceparent"), response.SpanContext().SpanID().String())
+	})
+}
+
+func TestJetStream(t *testing.T) {
+	t.Run("Should trace JetStream publish, consume and acknowledgements", func(t *testing.T) {
+		a := assert.New(t)
+		conn, opts, recorder := setup(t)
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		js, err := jetstream.New(conn)
+		a.NoError(err)
+		stream, err := js.CreateStream(ctx, jetstream.StreamConfig{Name: "USERS", Subjects: []string{"users.>"}})
+		a.NoError(err)
+		consumer, err := stream.CreateConsumer(ctx, jetstream.ConsumerConfig{Durable: "workers", AckPolicy: jetstream.AckExplicitPolicy})
+		a.NoError(err)
+
+		traced := tracing_nats.NewJetStream(js, append(opts, tracing_nats.WithAutoAcknowledge())...)
+		msg := &nats.Msg{Subject: "users.created", Data: []byte("42"), Header: nats.Header{}}
+		msg.Header.Set(jetstream.MsgIDHeader, "user-42")
+		ack, err := traced.PublishMsg(ctx, msg)
+		a.NoError(err)
+
+		done := make(chan struct{})
+		consumeCtx, err := consumer.Consume(traced.Handler(func(ctx context.Context, msg jetstream.Msg) error {
+			defer close(done)
+			return nil
+		}))
+		a.NoError(err)
+		<-done
+		consumeCtx.Stop()
+		time.Sleep(10 * time.Millisecond)
+
+		producer := spanNamed(recorder, "users.created publish")
+		a.Contains(producer.Attributes(), attribute.String("messaging.nats.stream", "USERS"))
+		a.Contains(producer.Attributes(), attribute.Int64("messaging.nats.stream_sequence", int64(ack.Sequence)))
+		a.Contains(producer.Attributes(), attribute.String("messaging.message.id", "user-42"))
+
+		process := spanNamed(recorder, "users.created process")
+		a.Equal(producer.SpanContext().SpanID(), process.Parent().SpanID())
+		a.Contains(process.Attributes(), attribute.String("messaging.nats.consumer", "workers"))
+		a.Contains(process.Attributes(), attribute.Int64("messaging.nats.num_delivered", 1))
+		a.Contains(process.Attributes(), attribute.String("messaging.nats.outcome", "ack"))
+	})
+}
@@ -1,30 +1,15 @@
 package rabbitmq
 
-// EventCarrier carries trace context in message headers
-type EventCarrier struct {
-	headers map[string]interface{}
-}
+import (
+	amqp "github.com/rabbitmq/amqp091-go"
 
-// Get retrieves a value from the headers
-func (c *EventCarrier) Get(key string) string {
-	if val, ok := c.headers[key]; ok {
-		if str, ok := val.(string); ok {
-			return str
-		}
-	}
-	return ""
-}
+	"github.com/weeb-vip/go-tracing-lib/utils/carrier"
+)
 
-// Set sets a value in the headers
-func (c *EventCarrier) Set(key, value string) {
-	c.headers[key] = value
-}
+// EventCarrier carries trace context in message headers, reading string and []byte values
+type EventCarrier = carrier.Map[any]
 
-// Keys returns all header keys
-func (c *EventCarrier) Keys() []string {
-	keys := make([]string, 0, len(c.headers))
-	for key := range c.headers {
-		keys = append(keys, key)
-	}
-	return keys
-}
\ No newline at end of file
+// NewEventCarrier creates a carrier over headers
+func NewEventCarrier(headers amqp.Table) *EventCarrier {
+	return carrier.NewAnyMap(headers)
+}
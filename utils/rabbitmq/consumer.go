@@ -5,23 +5,20 @@ import (
 
 	amqp "github.com/rabbitmq/amqp091-go"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
 )
 
 // ExtractTraceContextFromDelivery extracts trace context from amqp.Delivery
 func ExtractTraceContextFromDelivery(ctx context.Context, delivery amqp.Delivery) context.Context {
-	// Convert amqp.Table to map[string]interface{} if headers exist
-	headers := make(map[string]interface{})
-	if delivery.Headers != nil {
-		for k, v := range delivery.Headers {
-			headers[k] = v
-		}
-	}
-	carrier := &EventCarrier{headers: headers}
-	ctx = otel.GetTextMapPropagator().Extract(ctx, carrier)
-	return ctx
+	return extractHeaders(ctx, otel.GetTextMapPropagator(), delivery.Headers)
 }
 
 // ExtractTraceContext extracts trace context from a message with headers
 func ExtractTraceContext(ctx context.Context, delivery amqp.Delivery) context.Context {
 	return ExtractTraceContextFromDelivery(ctx, delivery)
-}
\ No newline at end of file
+}
+
+// extractHeaders returns ctx with the trace context found in headers
+func extractHeaders(ctx context.Context, propagator propagation.TextMapPropagator, headers amqp.Table) context.Context {
+	return propagator.Extract(ctx, NewEventCarrier(headers))
+}
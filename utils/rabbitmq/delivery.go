@@ -0,0 +1,220 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/weeb-vip/go-tracing-lib/internal/batchlinks"
+	"github.com/weeb-vip/go-tracing-lib/internal/queuelatency"
+	"github.com/weeb-vip/go-tracing-lib/logger"
+)
+
+const (
+	RedeliveredKey = attribute.Key("messaging.rabbitmq.redelivered")
+	OutcomeKey     = attribute.Key("messaging.rabbitmq.outcome")
+)
+
+// Handler processes a delivery within its consumer span
+type Handler func(ctx context.Context, delivery amqp.Delivery) error
+
+// BatchHandler processes deliveries within a single consumer span
+type BatchHandler func(ctx context.Context, deliveries []amqp.Delivery) error
+
+// Consumer processes deliveries of a queue in consumer spans
+type Consumer struct {
+	queue   string
+	config  *config
+	latency *queuelatency.Recorder
+}
+
+// NewConsumer creates a consumer for deliveries read from queue
+func NewConsumer(queue string, opts ...Option) *Consumer {
+	c := newConfig(opts)
+	return &Consumer{queue: queue, config: c, latency: queuelatency.New(c.meterProvider, instrumentationName)}
+}
+
+// Handle runs handler in a consumer span parented to, or linked with, the
+// publisher's span. Acks, nacks and rejects made through the delivery are
+// recorded on the span, and the context carries the trace-aware logger.
+// The time the delivery was queued is recorded from its publish timestamp.
+// The span records the delivery attempt and the last dead-lettering of the
+// delivery, and links to the first publish of deliveries retried with Republish.
+func (c *Consumer) Handle(ctx context.Context, delivery amqp.Delivery, handler Handler) error {
+	producerCtx := extractHeaders(ctx, c.config.propagator, delivery.Headers)
+
+	attrs := append(messageAttributes(delivery.Exchange, delivery.RoutingKey, delivery.MessageId, len(delivery.Body)),
+		semconv.MessagingOperationProcess,
+		semconv.MessagingSourceName(c.queue),
+		RedeliveredKey.Bool(delivery.Redelivered),
+	)
+	attrs = append(attrs, c.retryAttributes(delivery)...)
+	if delivery.ConsumerTag != "" {
+		attrs = append(attrs, semconv.MessagingConsumerID(delivery.ConsumerTag))
+	}
+	startOpts := []trace.SpanStartOption{
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(attrs...),
+	}
+	producer := trace.SpanContextFromContext(producerCtx)
+	if c.config.linkToProducer {
+		if producer.IsValid() {
+			startOpts = append(startOpts, trace.WithLinks(trace.Link{SpanContext: producer}))
+		}
+	} else {
+		ctx = producerCtx
+	}
+	// retries republished by a consumer continue its trace, the first publish is linked
+	if first := origin(c.config.propagator, delivery.Headers); first.IsValid() && !first.Equal(producer) {
+		startOpts = append(startOpts, trace.WithLinks(trace.Link{
+			SpanContext: first,
+			Attributes:  []attribute.KeyValue{LinkKey.String("origin")},
+		}))
+	}
+
+	ctx, span := c.config.tracer().Start(ctx, c.queue+" process", startOpts...)
+	defer span.End()
+	if latency, ok := c.recordLatency(ctx, span, delivery); ok {
+		queuelatency.SetAttribute(span, latency)
+	}
+
+	ctx = c.withLogger(ctx)
+	delivery = traceAcknowledger(delivery, span)
+
+	err := handler(ctx, delivery)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return errors.Join(err, c.acknowledge(delivery, err))
+}
+
+// HandleBatch runs handler in a single consumer span linked to the span of
+// every publisher, at most WithMaxLinks of them. Each link carries the
+// message ID of its delivery, and its attempt when it is a retry. Outcomes and logger are handled as in Handle.
+func (c *Consumer) HandleBatch(ctx context.Context, deliveries []amqp.Delivery, handler BatchHandler) error {
+	links := batchlinks.New(c.config.maxLinks)
+	for _, delivery := range deliveries {
+		var attrs []attribute.KeyValue
+		if delivery.MessageId != "" {
+			attrs = append(attrs, semconv.MessagingMessageID(delivery.MessageId))
+		}
+		if attempt := c.Attempt(delivery); attempt > 1 {
+			attrs = append(attrs, DeliveryAttemptKey.Int64(attempt))
+		}
+		producer := trace.SpanContextFromContext(extractHeaders(ctx, c.config.propagator, delivery.Headers))
+		links.Add(producer, attrs...)
+	}
+
+	startOpts := append([]trace.SpanStartOption{
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingSystem("rabbitmq"),
+			semconv.MessagingOperationProcess,
+			semconv.MessagingSourceName(c.queue),
+		),
+	}, links.StartOptions()...)
+	ctx, span := c.config.tracer().Start(ctx, c.queue+" process", startOpts...)
+	defer span.End()
+
+	times := make([]time.Time, len(deliveries))
+	for i, delivery := range deliveries {
+		times[i] = publishedAt(delivery)
+	}
+	c.latency.RecordLongest(ctx, span, times, c.latencyAttributes()...)
+
+	ctx = c.withLogger(ctx)
+	traced := make([]amqp.Delivery, len(deliveries))
+	for i, delivery := range deliveries {
+		traced[i] = traceAcknowledger(delivery, span)
+	}
+
+	err := handler(ctx, traced)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	errs := []error{err}
+	for _, delivery := range traced {
+		errs = append(errs, c.acknowledge(delivery, err))
+	}
+	return errors.Join(errs...)
+}
+
+// recordLatency records the queue latency of delivery
+func (c *Consumer) recordLatency(ctx context.Context, span trace.Span, delivery amqp.Delivery) (time.Duration, bool) {
+	return c.latency.Record(ctx, span, publishedAt(delivery), c.latencyAttributes()...)
+}
+
+// latencyAttributes are the attributes of the queue latency histogram
+func (c *Consumer) latencyAttributes() []attribute.KeyValue {
+	return []attribute.KeyValue{semconv.MessagingSystem("rabbitmq"), semconv.MessagingSourceName(c.queue)}
+}
+
+// publishedAt returns the publish time of delivery from its timestamp header,
+// or from its AMQP timestamp property with second precision
+func publishedAt(delivery amqp.Delivery) time.Time {
+	if publishedAt := queuelatency.Parse(delivery.Headers[queuelatency.Header]); !publishedAt.IsZero() {
+		return publishedAt
+	}
+	return delivery.Timestamp
+}
+
+func (c *Consumer) withLogger(ctx context.Context) context.Context {
+	return logger.ContextWithTraceFormats(ctx, c.config.logFormats...)
+}
+
+// acknowledge acks or nacks delivery depending on the handler error when WithAutoAcknowledge is set
+func (c *Consumer) acknowledge(delivery amqp.Delivery, err error) error {
+	if !c.config.autoAck || delivery.Acknowledger == nil {
+		return nil
+	}
+	if err == nil {
+		return delivery.Ack(false)
+	}
+	return delivery.Nack(false, c.config.requeue)
+}
+
+func traceAcknowledger(delivery amqp.Delivery, span trace.Span) amqp.Delivery {
+	if delivery.Acknowledger != nil {
+		delivery.Acknowledger = &tracedAcknowledger{acknowledger: delivery.Acknowledger, span: span}
+	}
+	return delivery
+}
+
+// tracedAcknowledger records the outcome of a delivery on its consumer span
+type tracedAcknowledger struct {
+	acknowledger amqp.Acknowledger
+	span         trace.Span
+}
+
+func (a *tracedAcknowledger) Ack(tag uint64, multiple bool) error {
+	return a.record("ack", false, a.acknowledger.Ack(tag, multiple))
+}
+
+func (a *tracedAcknowledger) Nack(tag uint64, multiple bool, requeue bool) error {
+	return a.record("nack", requeue, a.acknowledger.Nack(tag, multiple, requeue))
+}
+
+func (a *tracedAcknowledger) Reject(tag uint64, requeue bool) error {
+	return a.record("reject", requeue, a.acknowledger.Reject(tag, requeue))
+}
+
+func (a *tracedAcknowledger) record(outcome string, requeue bool, err error) error {
+	event := []attribute.KeyValue{OutcomeKey.String(outcome)}
+	if outcome != "ack" {
+		event = append(event, attribute.Bool("messaging.rabbitmq.requeue", requeue))
+	}
+	a.span.SetAttributes(OutcomeKey.String(outcome))
+	a.span.AddEvent(outcome, trace.WithAttributes(event...))
+	if err != nil {
+		a.span.RecordError(err)
+	}
+	return err
+}
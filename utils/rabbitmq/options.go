@@ -0,0 +1,127 @@
+package rabbitmq
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/weeb-vip/go-tracing-lib/internal/batchlinks"
+	"github.com/weeb-vip/go-tracing-lib/logger"
+	"github.com/weeb-vip/go-tracing-lib/providers"
+	"github.com/weeb-vip/go-tracing-lib/tracing/outbound"
+)
+
+const instrumentationName = "github.com/weeb-vip/go-tracing-lib/utils/rabbitmq"
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	propagator     propagation.TextMapPropagator
+	policy         *outbound.Policy
+	logFormats     []providers.LogFormat
+	linkToProducer bool
+	autoAck        bool
+	requeue        bool
+	maxLinks       int
+	timestamp      bool
+	meterProvider  metric.MeterProvider
+}
+
+// Option configures the traced publisher and consumer
+type Option func(*config)
+
+// WithTracerProvider sets the tracer provider, defaults to the global provider
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = provider
+	}
+}
+
+// WithPropagator sets the propagator used for message headers, defaults to the global propagator
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagator = propagator
+	}
+}
+
+// WithPropagationPolicy only propagates to each exchange the trace context its
+// rule allows, e.g. to keep baggage away from external consumers.
+// WrapPublishMessage does not know the exchange and applies the default rule.
+func WithPropagationPolicy(policy *outbound.Policy) Option {
+	return func(c *config) {
+		c.policy = policy
+	}
+}
+
+// WithLogFormats selects the trace identifier formats of the consumer logger,
+// defaults to logger.DefaultTraceFormats
+func WithLogFormats(formats ...providers.LogFormat) Option {
+	return func(c *config) {
+		c.logFormats = formats
+	}
+}
+
+// WithLinkToProducer starts consumer spans in a new trace linked to the
+// producer span, instead of continuing the producer's trace
+func WithLinkToProducer() Option {
+	return func(c *config) {
+		c.linkToProducer = true
+	}
+}
+
+// WithAutoAcknowledge acks deliveries whose handler succeeds and nacks the
+// others, requeueing them if requeue is set
+func WithAutoAcknowledge(requeue bool) Option {
+	return func(c *config) {
+		c.autoAck = true
+		c.requeue = requeue
+	}
+}
+
+// WithMaxLinks caps the producer links of a batch span, defaults to 128
+func WithMaxLinks(max int) Option {
+	return func(c *config) {
+		c.maxLinks = max
+	}
+}
+
+// WithPublishTimestamp stamps published messages with their publish time, in
+// Unix milliseconds, so that consumers record how long they were queued
+func WithPublishTimestamp() Option {
+	return func(c *config) {
+		c.timestamp = true
+	}
+}
+
+// WithMeterProvider sets the meter provider of the queue latency histogram, defaults to the global provider
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = provider
+	}
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{
+		tracerProvider: otel.GetTracerProvider(),
+		propagator:     otel.GetTextMapPropagator(),
+		logFormats:     logger.DefaultTraceFormats,
+		maxLinks:       batchlinks.DefaultMax,
+		meterProvider:  otel.GetMeterProvider(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *config) tracer() trace.Tracer {
+	return c.tracerProvider.Tracer(instrumentationName)
+}
+
+// outbound returns the propagator injecting the trace context sent to destination
+func (c *config) outbound(destination string) propagation.TextMapPropagator {
+	if c.policy == nil {
+		return c.propagator
+	}
+	return c.policy.Propagator(destination, c.propagator)
+}
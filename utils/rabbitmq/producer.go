@@ -4,28 +4,33 @@ import (
 	"context"
 
 	amqp "github.com/rabbitmq/amqp091-go"
-	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+
+	"github.com/weeb-vip/go-tracing-lib/internal/queuelatency"
 )
 
-// WrapPublishMessage wraps an AMQP message with tracing context
-func WrapPublishMessage(ctx context.Context, msg amqp.Publishing) amqp.Publishing {
-	if msg.Headers == nil {
-		msg.Headers = make(amqp.Table)
-	}
+// WrapPublishMessage wraps an AMQP message with tracing context, and its
+// publish time with WithPublishTimestamp
+func WrapPublishMessage(ctx context.Context, msg amqp.Publishing, opts ...Option) amqp.Publishing {
+	c := newConfig(opts)
+	return c.stamp(injectHeaders(ctx, c.outbound(""), msg))
+}
 
-	// Create a carrier from the headers
-	headers := make(map[string]interface{})
+// injectHeaders adds the trace context of ctx to a copy of the message headers
+func injectHeaders(ctx context.Context, propagator propagation.TextMapPropagator, msg amqp.Publishing) amqp.Publishing {
+	headers := make(amqp.Table, len(msg.Headers)+2)
 	for k, v := range msg.Headers {
 		headers[k] = v
 	}
+	propagator.Inject(ctx, NewEventCarrier(headers))
+	msg.Headers = headers
+	return msg
+}
 
-	carrier := &EventCarrier{headers: headers}
-	otel.GetTextMapPropagator().Inject(ctx, carrier)
-
-	// Copy back to amqp.Table
-	for k, v := range carrier.headers {
-		msg.Headers[k] = v
+// stamp adds the publish time header when WithPublishTimestamp is set, msg must have headers
+func (c *config) stamp(msg amqp.Publishing) amqp.Publishing {
+	if c.timestamp {
+		msg.Headers[queuelatency.Header] = queuelatency.Stamp()
 	}
-
 	return msg
-}
\ No newline at end of file
+}
@@ -0,0 +1,71 @@
+package rabbitmq
+
+import (
+	"context"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Channel is the publishing side of *amqp.Channel
+type Channel interface {
+	PublishWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error
+}
+
+// Publisher publishes messages in a producer span and carries the span in the message headers
+type Publisher struct {
+	channel Channel
+	config  *config
+}
+
+// NewPublisher creates a publisher over channel, usually an *amqp.Channel
+func NewPublisher(channel Channel, opts ...Option) *Publisher {
+	return &Publisher{channel: channel, config: newConfig(opts)}
+}
+
+// Publish publishes msg like amqp.Channel.PublishWithContext
+func (p *Publisher) Publish(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error {
+	attrs := append(messageAttributes(exchange, key, msg.MessageId, len(msg.Body)), semconv.MessagingOperationPublish)
+	ctx, span := p.config.tracer().Start(ctx, destinationName(exchange, key)+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(attrs...),
+	)
+	defer span.End()
+
+	msg = p.config.stamp(injectHeaders(ctx, p.config.outbound(destinationName(exchange, key)), msg))
+	if err := p.channel.PublishWithContext(ctx, exchange, key, mandatory, immediate, msg); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	return nil
+}
+
+func messageAttributes(exchange, key, messageID string, size int) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		semconv.MessagingSystem("rabbitmq"),
+		semconv.MessagingDestinationName(exchange),
+		semconv.MessagingMessagePayloadSizeBytes(size),
+	}
+	if key != "" {
+		attrs = append(attrs, semconv.MessagingRabbitmqDestinationRoutingKey(key))
+	}
+	if messageID != "" {
+		attrs = append(attrs, semconv.MessagingMessageID(messageID))
+	}
+	return attrs
+}
+
+// destinationName names spans after the exchange, or the routing key for the default exchange
+func destinationName(exchange, key string) string {
+	if exchange != "" {
+		return exchange
+	}
+	if key != "" {
+		return key
+	}
+	return "(default)"
+}
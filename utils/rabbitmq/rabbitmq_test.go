@@ -0,0 +1,379 @@
+package rabbitmq_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+
+	"github.com/weeb-vip/go-tracing-lib/tracing/outbound"
+	"github.com/weeb-vip/go-tracing-lib/utils/rabbitmq"
+)
+
+type fakeChannel struct {
+	published []amqp.Publishing
+	err       error
+}
+
+func (c *fakeChannel) PublishWithContext(_ context.Context, _, _ string, _, _ bool, msg amqp.Publishing) error {
+	c.published = append(c.published, msg)
+	return c.err
+}
+
+type fakeAcknowledger struct {
+	acks    int
+	nacks   int
+	requeue bool
+}
+
+func (a *fakeAcknowledger) Ack(uint64, bool) error {
+	a.acks++
+	return nil
+}
+
+func (a *fakeAcknowledger) Nack(_ uint64, _ bool, requeue bool) error {
+	a.nacks++
+	a.requeue = requeue
+	return nil
+}
+
+func (a *fakeAcknowledger) Reject(uint64, bool) error {
+	return nil
+}
+
+func setup() ([]rabbitmq.Option, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	return []rabbitmq.Option{
+		rabbitmq.WithTracerProvider(trace.NewTracerProvider(trace.WithSpanProcessor(recorder))),
+		rabbitmq.WithPropagator(propagation.TraceContext{}),
+	}, recorder
+}
+
+func publish(t *testing.T, opts []rabbitmq.Option) amqp.Publishing {
+	channel := &fakeChannel{}
+	err := rabbitmq.NewPublisher(channel, opts...).Publish(context.Background(), "events", "user.created", false, false, amqp.Publishing{
+		MessageId: "42",
+		Body:      []byte("payload"),
+	})
+	assert.NoError(t, err)
+	return channel.published[0]
+}
+
+func TestPublisher(t *testing.T) {
+	t.Run("Should publish in a producer span and inject its context", func(t *testing.T) {
+		a := assert.New(t)
+		opts, recorder := setup()
+
+		msg := publish(t, opts)
+
+		spans := recorder.Ended()
+		a.Len(spans, 1)
+		a.Equal("events publish", spans[0].Name())
+		a.Equal(oteltrace.SpanKindProducer, spans[0].SpanKind())
+		a.Contains(spans[0].Attributes(), attribute.String("messaging.system", "rabbitmq"))
+		a.Contains(spans[0].Attributes(), attribute.String("messaging.destination.name", "events"))
+		a.Contains(spans[0].Attributes(), attribute.String("messaging.rabbitmq.destination.routing_key", "user.created"))
+		a.Contains(spans[0].Attributes(), attribute.String("messaging.message.id", "42"))
+		a.Contains(spans[0].Attributes(), attribute.Int("messaging.message.payload_size_bytes", 7))
+		a.Contains(msg.Headers["traceparent"], spans[0].SpanContext().SpanID().String())
+	})
+
+	t.Run("Should record publish errors", func(t *testing.T) {
+		a := assert.New(t)
+		opts, recorder := setup()
+		channel := &fakeChannel{err: amqp.ErrClosed}
+
+		err := rabbitmq.NewPublisher(channel, opts...).Publish(context.Background(), "", "jobs", false, false, amqp.Publishing{})
+
+		a.ErrorIs(err, amqp.ErrClosed)
+		a.Equal("jobs publish", recorder.Ended()[0].Name())
+		a.Equal(codes.Error, recorder.Ended()[0].Status().Code)
+	})
+}
+
+func TestConsumer(t *testing.T) {
+	t.Run("Should process deliveries in a child of the producer span", func(t *testing.T) {
+		a := assert.New(t)
+		opts, recorder := setup()
+		msg := publish(t, opts)
+		acknowledger := &fakeAcknowledger{}
+
+		err := rabbitmq.NewConsumer("users", opts...).Handle(context.Background(), amqp.Delivery{
+			Acknowledger: acknowledger,
+			Headers:      msg.Headers,
+			Exchange:     "events",
+			RoutingKey:   "user.created",
+			Redelivered:  true,
+			Body:         msg.Body,
+		}, func(ctx context.Context, delivery amqp.Delivery) error {
+			return delivery.Ack(false)
+		})
+
+		a.NoError(err)
+		a.Equal(1, acknowledger.acks)
+		producer, consumer := recorder.Ended()[0], recorder.Ended()[1]
+		a.Equal("users process", consumer.Name())
+		a.Equal(oteltrace.SpanKindConsumer, consumer.SpanKind())
+		a.Equal(producer.SpanContext().SpanID(), consumer.Parent().SpanID())
+		a.Contains(consumer.Attributes(), attribute.Bool("messaging.rabbitmq.redelivered", true))
+		a.Contains(consumer.Attributes(), attribute.String("messaging.rabbitmq.outcome", "ack"))
+	})
+
+	t.Run("Should link to the producer span and nack failed deliveries", func(t *testing.T) {
+		a := assert.New(t)
+		opts, recorder := setup()
+		msg := publish(t, opts)
+		acknowledger := &fakeAcknowledger{}
+		failure := errors.New("boom")
+
+		consumer := rabbitmq.NewConsumer("users", append(opts, rabbitmq.WithLinkToProducer(), rabbitmq.WithAutoAcknowledge(true))...)
+		err := consumer.Handle(context.Background(), amqp.Delivery{Acknowledger: acknowledger, Headers: msg.Headers},
+			func(ctx context.Context, delivery amqp.Delivery) error {
+				return failure
+			})
+
+		a.ErrorIs(err, failure)
+		a.Equal(1, acknowledger.nacks)
+		a.True(acknowledger.requeue)
+		producer, span := recorder.Ended()[0], recorder.Ended()[1]
+		a.False(span.Parent().IsValid())
+		a.Len(span.Links(), 1)
+		a.Equal(producer.SpanContext().TraceID(), span.Links()[0].SpanContext.TraceID())
+		a.Equal(codes.Error, span.Status().Code)
+		a.Contains(span.Attributes(), attribute.String("messaging.rabbitmq.outcome", "nack"))
+	})
+}
+
+func TestConsumerBatch(t *testing.T) {
+	t.Run("Should process a batch in one span linked to every producer", func(t *testing.T) {
+		a := assert.New(t)
+		opts, recorder := setup()
+		first, second, third := publish(t, opts), publish(t, opts), publish(t, opts)
+		acknowledger := &fakeAcknowledger{}
+
+		consumer := rabbitmq.NewConsumer("users", append(opts, rabbitmq.WithMaxLinks(2), rabbitmq.WithAutoAcknowledge(false))...)
+		err := consumer.HandleBatch(context.Background(), []amqp.Delivery{
+			{Acknowledger: acknowledger, Headers: first.Headers, MessageId: "1"},
+			{Acknowledger: acknowledger, Headers: second.Headers, MessageId: "2"},
+			{Acknowledger: acknowledger, Headers: third.Headers, MessageId: "3"},
+			{Acknowledger: acknowledger},
+		}, func(ctx context.Context, deliveries []amqp.Delivery) error {
+			a.Len(deliveries, 4)
+			return nil
+		})
+
+		a.NoError(err)
+		a.Equal(4, acknowledger.acks)
+		spans := recorder.Ended()
+		batch := spans[len(spans)-1]
+		a.Equal("users process", batch.Name())
+		a.False(batch.Parent().IsValid())
+		a.Len(batch.Links(), 2)
+		a.Equal(spans[0].SpanContext().SpanID(), batch.Links()[0].SpanContext.SpanID())
+		a.Contains(batch.Links()[0].Attributes, attribute.String("messaging.message.id", "1"))
+		a.Contains(batch.Attributes(), attribute.Int("messaging.batch.message_count", 4))
+		a.Contains(batch.Attributes(), attribute.Int("messaging.batch.links_dropped", 1))
+	})
+}
+
+func TestConsumerLatency(t *testing.T) {
+	t.Run("Should record the queue latency of stamped deliveries", func(t *testing.T) {
+		a := assert.New(t)
+		opts, recorder := setup()
+		reader := metric.NewManualReader()
+		opts = append(opts, rabbitmq.WithPublishTimestamp(), rabbitmq.WithMeterProvider(metric.NewMeterProvider(metric.WithReader(reader))))
+		msg := publish(t, opts)
+		a.Contains(msg.Headers, "x-published-at")
+
+		err := rabbitmq.NewConsumer("users", opts...).Handle(context.Background(), amqp.Delivery{
+			Acknowledger: &fakeAcknowledger{},
+			Headers:      msg.Headers,
+		}, func(ctx context.Context, delivery amqp.Delivery) error {
+			return nil
+		})
+
+		a.NoError(err)
+		consumer := recorder.Ended()[1]
+		a.True(hasAttribute(consumer.Attributes(), "messaging.queue_latency"))
+
+		var metrics metricdata.ResourceMetrics
+		a.NoError(reader.Collect(context.Background(), &metrics))
+		histogram := metrics.ScopeMetrics[0].Metrics[0]
+		a.Equal("messaging.queue_latency", histogram.Name)
+		a.Equal("ms", histogram.Unit)
+		a.Equal(uint64(1), histogram.Data.(metricdata.Histogram[float64]).DataPoints[0].Count)
+	})
+
+	t.Run("Should record skewed latencies as 0", func(t *testing.T) {
+		a := assert.New(t)
+		opts, recorder := setup()
+
+		err := rabbitmq.NewConsumer("users", opts...).Handle(context.Background(), amqp.Delivery{
+			Acknowledger: &fakeAcknowledger{},
+			Headers:      amqp.Table{"x-published-at": time.Now().Add(time.Minute).UnixMilli()},
+		}, func(ctx context.Context, delivery amqp.Delivery) error {
+			return nil
+		})
+
+		a.NoError(err)
+		consumer := recorder.Ended()[0]
+		a.Contains(consumer.Attributes(), attribute.Int64("messaging.queue_latency", 0))
+		a.Contains(consumer.Attributes(), attribute.Bool("messaging.queue_latency.clock_skew", true))
+	})
+
+	t.Run("Should not record the latency of unstamped deliveries", func(t *testing.T) {
+		a := assert.New(t)
+		opts, recorder := setup()
+
+		err := rabbitmq.NewConsumer("users", opts...).Handle(context.Background(), amqp.Delivery{
+			Acknowledger: &fakeAcknowledger{},
+		}, func(ctx context.Context, delivery amqp.Delivery) error {
+			return nil
+		})
+
+		a.NoError(err)
+		a.False(hasAttribute(recorder.Ended()[0].Attributes(), "messaging.queue_latency"))
+	})
+}
+
+func hasAttribute(attrs []attribute.KeyValue, key attribute.Key) bool {
+	for _, attr := range attrs {
+		if attr.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func TestConsumerRetries(t *testing.T) {
+	t.Run("Should record the attempt and dead-letter reason of dead-lettered deliveries", func(t *testing.T) {
+		a := assert.New(t)
+		opts, recorder := setup()
+		msg := publish(t, opts)
+		headers := amqp.Table{"x-death": []any{
+			amqp.Table{"count": int64(2), "reason": "expired", "queue": "users.retry", "routing-keys": []any{"user.created"}},
+			amqp.Table{"count": int64(1), "reason": "rejected", "queue": "users"},
+		}}
+		for k, v := range msg.Headers {
+			headers[k] = v
+		}
+
+		deaths := rabbitmq.Deaths(headers)
+		a.Len(deaths, 2)
+		a.Equal([]string{"user.created"}, deaths[0].RoutingKeys)
+
+		err := rabbitmq.NewConsumer("users", opts...).Handle(context.Background(), amqp.Delivery{
+			Headers:     headers,
+			Redelivered: true,
+		}, func(ctx context.Context, delivery amqp.Delivery) error {
+			return nil
+		})
+
+		a.NoError(err)
+		producer, consumer := recorder.Ended()[0], recorder.Ended()[1]
+		a.Equal(producer.SpanContext().SpanID(), consumer.Parent().SpanID())
+		// the expiries in users.retry route the rejected delivery back and are not attempts
+		a.Contains(consumer.Attributes(), attribute.Int64("messaging.rabbitmq.delivery_attempt", 3))
+		a.Contains(consumer.Attributes(), attribute.Int64("messaging.rabbitmq.death_count", 1))
+		a.Contains(consumer.Attributes(), attribute.String("messaging.rabbitmq.dead_letter.reason", "expired"))
+		a.Contains(consumer.Attributes(), attribute.String("messaging.rabbitmq.dead_letter.queue", "users.retry"))
+	})
+
+	t.Run("Should count a retry through a delay queue once", func(t *testing.T) {
+		a := assert.New(t)
+		consumer := rabbitmq.NewConsumer("work")
+		delivery := amqp.Delivery{Headers: amqp.Table{"x-death": []any{
+			amqp.Table{"count": int64(1), "reason": "expired", "queue": "work.delay"},
+			amqp.Table{"count": int64(1), "reason": "rejected", "queue": "work"},
+		}}}
+
+		a.Equal(int64(2), consumer.Attempt(delivery))
+	})
+
+	t.Run("Should link republished deliveries to the first publish", func(t *testing.T) {
+		a := assert.New(t)
+		opts, recorder := setup()
+		msg := publish(t, opts)
+		consumer := rabbitmq.NewConsumer("users", opts...)
+		channel := &fakeChannel{}
+
+		for i := 0; i < 2; i++ {
+			err := consumer.Handle(context.Background(), amqp.Delivery{Headers: msg.Headers, MessageId: "42"},
+				func(ctx context.Context, delivery amqp.Delivery) error {
+					return rabbitmq.NewPublisher(channel, opts...).Publish(ctx, "retry", "user.created", false, false,
+						consumer.Republish(delivery))
+				})
+			a.NoError(err)
+			msg = channel.published[i]
+		}
+		a.Equal(int64(2), msg.Headers["x-retry-count"])
+		a.Equal("42", msg.MessageId)
+
+		err := consumer.Handle(context.Background(), amqp.Delivery{Headers: msg.Headers}, func(ctx context.Context, delivery amqp.Delivery) error {
+			return nil
+		})
+
+		a.NoError(err)
+		spans := recorder.Ended()
+		first, last := spans[0], spans[len(spans)-1]
+		a.Contains(last.Attributes(), attribute.Int64("messaging.rabbitmq.delivery_attempt", 3))
+		a.Equal("retry publish", spans[len(spans)-3].Name())
+		a.Equal(spans[len(spans)-3].SpanContext().SpanID(), last.Parent().SpanID())
+		a.Len(last.Links(), 1)
+		a.Equal(first.SpanContext().SpanID(), last.Links()[0].SpanContext.SpanID())
+		a.Contains(last.Links()[0].Attributes, attribute.String("messaging.rabbitmq.link", "origin"))
+	})
+
+	t.Run("Should drop the broker headers and publish time of the retried delivery", func(t *testing.T) {
+		a := assert.New(t)
+		opts, _ := setup()
+		msg := publish(t, opts)
+		headers := amqp.Table{
+			"x-published-at":   int64(1700000000000),
+			"x-delivery-count": int64(2),
+			"x-death":          []any{amqp.Table{"count": int64(1), "reason": "rejected", "queue": "users"}},
+		}
+		for k, v := range msg.Headers {
+			headers[k] = v
+		}
+
+		consumer := rabbitmq.NewConsumer("users", opts...)
+		republished := consumer.Republish(amqp.Delivery{Headers: headers, Timestamp: time.Unix(1700000000, 0)})
+
+		a.NotContains(republished.Headers, "x-delivery-count")
+		a.NotContains(republished.Headers, "x-published-at")
+		a.True(republished.Timestamp.IsZero())
+		a.NotContains(republished.Headers, "x-death")
+		a.Equal(msg.Headers["traceparent"], republished.Headers["traceparent"])
+		// the first delivery, one dead-lettering and two redeliveries came before
+		a.Equal(int64(4), republished.Headers["x-retry-count"])
+		a.Equal(int64(5), consumer.Attempt(amqp.Delivery{Headers: republished.Headers}))
+	})
+}
+
+func TestPropagationPolicy(t *testing.T) {
+	t.Run("Should not propagate the context to external exchanges", func(t *testing.T) {
+		a := assert.New(t)
+		opts, _ := setup()
+		opts = append(opts, rabbitmq.WithPropagationPolicy(outbound.New(outbound.Allow("events", outbound.All))))
+		channel := &fakeChannel{}
+		publisher := rabbitmq.NewPublisher(channel, opts...)
+
+		a.NoError(publisher.Publish(context.Background(), "events", "user.created", false, false, amqp.Publishing{}))
+		a.NoError(publisher.Publish(context.Background(), "partner.events", "user.created", false, false, amqp.Publishing{}))
+
+		a.Contains(channel.published[0].Headers, "traceparent")
+		a.NotContains(channel.published[1].Headers, "traceparent")
+	})
+}
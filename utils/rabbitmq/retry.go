@@ -0,0 +1,182 @@
+package rabbitmq
+
+import (
+	"context"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/weeb-vip/go-tracing-lib/internal/queuelatency"
+)
+
+const (
+	DeliveryAttemptKey  = attribute.Key("messaging.rabbitmq.delivery_attempt")
+	DeathCountKey       = attribute.Key("messaging.rabbitmq.death_count")
+	DeadLetterReasonKey = attribute.Key("messaging.rabbitmq.dead_letter.reason")
+	DeadLetterQueueKey  = attribute.Key("messaging.rabbitmq.dead_letter.queue")
+	// LinkKey tells the links of a retried delivery apart, it is "origin" on the link to the first publish
+	LinkKey = attribute.Key("messaging.rabbitmq.link")
+
+	// RetryCountHeader counts the attempts made before Republish published a delivery again
+	RetryCountHeader = "x-retry-count"
+
+	deathHeader         = "x-death"
+	deliveryCountHeader = "x-delivery-count"
+	originHeaderPrefix  = "x-original-"
+)
+
+// Death is an entry of the x-death header RabbitMQ adds when dead-lettering a message
+type Death struct {
+	Queue       string
+	Exchange    string
+	Reason      string
+	RoutingKeys []string
+	Count       int64
+	Time        time.Time
+}
+
+// Deaths returns the x-death entries of headers, the most recent first
+func Deaths(headers amqp.Table) []Death {
+	entries, _ := headers[deathHeader].([]any)
+	deaths := make([]Death, 0, len(entries))
+	for _, entry := range entries {
+		table, ok := entry.(amqp.Table)
+		if !ok {
+			continue
+		}
+		death := Death{Count: toInt64(table["count"])}
+		death.Queue, _ = table["queue"].(string)
+		death.Exchange, _ = table["exchange"].(string)
+		death.Reason, _ = table["reason"].(string)
+		death.Time, _ = table["time"].(time.Time)
+		keys, _ := table["routing-keys"].([]any)
+		for _, key := range keys {
+			if key, ok := key.(string); ok {
+				death.RoutingKeys = append(death.RoutingKeys, key)
+			}
+		}
+		deaths = append(deaths, death)
+	}
+	return deaths
+}
+
+// Attempt returns which delivery attempt of a message delivery is, counting
+// its republishes, broker redeliveries and dead-letterings from the queue of
+// the consumer. Dead-letterings from other queues, e.g. the expiry of a delay
+// queue that routes the message back, belong to the same attempt. Redeliveries
+// are counted from the x-delivery-count header of quorum queues, other queues
+// only tell whether the delivery was redelivered so they count at most one.
+func (c *Consumer) Attempt(delivery amqp.Delivery) int64 {
+	attempt := 1 + toInt64(delivery.Headers[RetryCountHeader]) + c.deathCount(delivery)
+	if count, ok := delivery.Headers[deliveryCountHeader]; ok {
+		attempt += toInt64(count)
+	} else if delivery.Redelivered {
+		attempt++
+	}
+	return attempt
+}
+
+// deathCount returns how many times delivery was dead-lettered from the queue of the consumer
+func (c *Consumer) deathCount(delivery amqp.Delivery) int64 {
+	var count int64
+	for _, death := range Deaths(delivery.Headers) {
+		if death.Queue == c.queue {
+			count += death.Count
+		}
+	}
+	return count
+}
+
+// Republish returns a publishing of delivery to retry it through another
+// exchange, e.g. a delay queue. It keeps the trace context of the first
+// publish of the message so that the consumer spans of every retry link back
+// to it. The x-death and x-delivery-count headers describe the delivery being
+// retried, not the new message, so they are dropped and the attempts they
+// counted move to the RetryCountHeader. The publish time is dropped too, the
+// publisher stamps the retry with WithPublishTimestamp.
+func (c *Consumer) Republish(delivery amqp.Delivery) amqp.Publishing {
+	headers := make(amqp.Table, len(delivery.Headers)+1)
+	for k, v := range delivery.Headers {
+		switch k {
+		case deathHeader, deliveryCountHeader, queuelatency.Header:
+			continue
+		}
+		headers[k] = v
+	}
+	if !origin(c.config.propagator, headers).IsValid() {
+		for _, field := range c.config.propagator.Fields() {
+			if value, ok := headers[field]; ok {
+				headers[originHeaderPrefix+field] = value
+			}
+		}
+	}
+	headers[RetryCountHeader] = c.Attempt(delivery)
+
+	return amqp.Publishing{
+		Headers:         headers,
+		ContentType:     delivery.ContentType,
+		ContentEncoding: delivery.ContentEncoding,
+		DeliveryMode:    delivery.DeliveryMode,
+		Priority:        delivery.Priority,
+		CorrelationId:   delivery.CorrelationId,
+		ReplyTo:         delivery.ReplyTo,
+		Expiration:      delivery.Expiration,
+		MessageId:       delivery.MessageId,
+		Type:            delivery.Type,
+		UserId:          delivery.UserId,
+		AppId:           delivery.AppId,
+		Body:            delivery.Body,
+	}
+}
+
+// retryAttributes describes the attempt of delivery, and its last dead-lettering if any
+func (c *Consumer) retryAttributes(delivery amqp.Delivery) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{DeliveryAttemptKey.Int64(c.Attempt(delivery))}
+	deaths := Deaths(delivery.Headers)
+	if len(deaths) == 0 {
+		return attrs
+	}
+	return append(attrs,
+		DeathCountKey.Int64(c.deathCount(delivery)),
+		DeadLetterReasonKey.String(deaths[0].Reason),
+		DeadLetterQueueKey.String(deaths[0].Queue),
+	)
+}
+
+// origin returns the span context of the first publish kept by Republish
+func origin(propagator propagation.TextMapPropagator, headers amqp.Table) trace.SpanContext {
+	fields := make(amqp.Table, len(propagator.Fields()))
+	for _, field := range propagator.Fields() {
+		if value, ok := headers[originHeaderPrefix+field]; ok {
+			fields[field] = value
+		}
+	}
+	return trace.SpanContextFromContext(propagator.Extract(context.Background(), NewEventCarrier(fields)))
+}
+
+// toInt64 reads the integer types AMQP tables decode to
+func toInt64(value any) int64 {
+	switch v := value.(type) {
+	case int64:
+		return v
+	case int32:
+		return int64(v)
+	case int16:
+		return int64(v)
+	case int8:
+		return int64(v)
+	case int:
+		return int64(v)
+	case uint8:
+		return int64(v)
+	case uint16:
+		return int64(v)
+	case uint32:
+		return int64(v)
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,46 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/weeb-vip/go-tracing-lib/internal/batchlinks"
+	"github.com/weeb-vip/go-tracing-lib/internal/queuelatency"
+	"github.com/weeb-vip/go-tracing-lib/utils/carrier"
+)
+
+// StartBatchSpan extracts the trace context of every message and starts a
+// consumer span named name linked to each of them. Messages with an
+// ID() string method get a messaging.message.id attribute on their link.
+// Messages stamped with WithPublishTimestamp have their queue latency recorded.
+func StartBatchSpan[T any](ctx context.Context, name string, messages []RedisMessage[T], opts ...Option) (context.Context, trace.Span) {
+	c := newConfig(opts)
+
+	links := batchlinks.New(c.maxLinks)
+	for _, message := range messages {
+		var attrs []attribute.KeyValue
+		if identified, ok := message.(interface{ ID() string }); ok {
+			attrs = append(attrs, semconv.MessagingMessageID(identified.ID()))
+		}
+		producer := trace.SpanContextFromContext(c.propagator.Extract(ctx, carrier.NewStringMap(message.Headers())))
+		links.Add(producer, attrs...)
+	}
+
+	system := semconv.MessagingSystemKey.String("redis")
+	startOpts := append([]trace.SpanStartOption{
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(system, semconv.MessagingOperationProcess),
+	}, links.StartOptions()...)
+	ctx, span := c.tracerProvider.Tracer(instrumentationName).Start(ctx, name, startOpts...)
+
+	times := make([]time.Time, len(messages))
+	for i, message := range messages {
+		times[i] = queuelatency.Parse(message.Headers()[queuelatency.Header])
+	}
+	c.latency().RecordLongest(ctx, span, times, system)
+	return ctx, span
+}
@@ -0,0 +1,131 @@
+package redis_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+
+	"github.com/weeb-vip/go-tracing-lib/utils/redis"
+)
+
+type message struct {
+	id      string
+	headers map[string]string
+}
+
+func (m *message) Headers() map[string]string           { return m.headers }
+func (m *message) SetHeaders(headers map[string]string) { m.headers = headers }
+func (m *message) GetMsg() string                       { return "" }
+func (m *message) ID() string                           { return m.id }
+
+func TestStartBatchSpan(t *testing.T) {
+	t.Run("Should link the batch span to every producer", func(t *testing.T) {
+		a := assert.New(t)
+		recorder := tracetest.NewSpanRecorder()
+		provider := trace.NewTracerProvider(trace.WithSpanProcessor(recorder))
+		propagator := propagation.TraceContext{}
+
+		var messages []redis.RedisMessage[string]
+		for _, id := range []string{"1", "2", "3"} {
+			ctx, span := provider.Tracer("test").Start(context.Background(), "publish")
+			msg := &message{id: id, headers: map[string]string{}}
+			propagator.Inject(ctx, propagation.MapCarrier(msg.headers))
+			span.End()
+			messages = append(messages, msg)
+		}
+
+		_, span := redis.StartBatchSpan(context.Background(), "events process", messages,
+			redis.WithTracerProvider(provider), redis.WithPropagator(propagator), redis.WithMaxLinks(2))
+		span.End()
+
+		spans := recorder.Ended()
+		batch := spans[len(spans)-1]
+		a.Len(batch.Links(), 2)
+		a.Equal(spans[0].SpanContext().TraceID(), batch.Links()[0].SpanContext.TraceID())
+		a.Contains(batch.Links()[1].Attributes, attribute.String("messaging.message.id", "2"))
+		a.Contains(batch.Attributes(), attribute.Int("messaging.batch.message_count", 3))
+		a.Contains(batch.Attributes(), attribute.Int("messaging.batch.links_dropped", 1))
+	})
+}
+
+func TestWrapPublishMessage(t *testing.T) {
+	t.Run("Should stamp messages with their publish time", func(t *testing.T) {
+		a := assert.New(t)
+		recorder := tracetest.NewSpanRecorder()
+		provider := trace.NewTracerProvider(trace.WithSpanProcessor(recorder))
+		propagator := propagation.TraceContext{}
+
+		ctx, span := provider.Tracer("test").Start(context.Background(), "publish")
+		published := redis.WrapPublishMessage[string](ctx, &message{id: "1"},
+			redis.WithPropagator(propagator), redis.WithPublishTimestamp())
+		span.End()
+
+		a.NotEmpty(published.Headers()["traceparent"])
+		a.NotEmpty(published.Headers()["x-published-at"])
+
+		stamped := &message{id: "2", headers: map[string]string{
+			"x-published-at": strconv.FormatInt(time.Now().Add(-time.Second).UnixMilli(), 10),
+		}}
+		_, batch := redis.StartBatchSpan(context.Background(), "events process", []redis.RedisMessage[string]{published, stamped},
+			redis.WithTracerProvider(provider), redis.WithPropagator(propagator))
+		batch.End()
+
+		var latency int64
+		for _, attr := range recorder.Ended()[1].Attributes() {
+			if attr.Key == "messaging.queue_latency" {
+				latency = attr.Value.AsInt64()
+			}
+		}
+		a.GreaterOrEqual(latency, int64(1000))
+	})
+}
+
+func TestExtractTraceContext(t *testing.T) {
+	t.Run("Should record the queue latency of stamped messages", func(t *testing.T) {
+		a := assert.New(t)
+		reader := metric.NewManualReader()
+		meterProvider := metric.NewMeterProvider(metric.WithReader(reader))
+		propagator := propagation.TraceContext{}
+
+		ctx, span := trace.NewTracerProvider().Tracer("test").Start(context.Background(), "publish")
+		published := redis.WrapPublishMessage[string](ctx, &message{id: "1"},
+			redis.WithPropagator(propagator), redis.WithPublishTimestamp())
+		span.End()
+
+		for i := 0; i < 2; i++ {
+			extracted := redis.ExtractTraceContext[string](context.Background(), published,
+				redis.WithPropagator(propagator), redis.WithMeterProvider(meterProvider))
+			a.Equal(span.SpanContext().TraceID(), oteltrace.SpanContextFromContext(extracted).TraceID())
+		}
+		redis.ExtractTraceContext[string](context.Background(), &message{id: "2"},
+			redis.WithPropagator(propagator), redis.WithMeterProvider(meterProvider))
+
+		var metrics metricdata.ResourceMetrics
+		a.NoError(reader.Collect(context.Background(), &metrics))
+		histogram := metrics.ScopeMetrics[0].Metrics[0]
+		a.Equal("messaging.queue_latency", histogram.Name)
+		a.Equal(uint64(2), histogram.Data.(metricdata.Histogram[float64]).DataPoints[0].Count)
+	})
+	t.Run("Should not set attributes on the span of the caller", func(t *testing.T) {
+		a := assert.New(t)
+		recorder := tracetest.NewSpanRecorder()
+		ctx, span := trace.NewTracerProvider(trace.WithSpanProcessor(recorder)).Tracer("test").Start(context.Background(), "caller")
+
+		future := strconv.FormatInt(time.Now().Add(time.Hour).UnixMilli(), 10)
+		redis.ExtractTraceContext[string](ctx, &message{id: "1", headers: map[string]string{"x-published-at": future}},
+			redis.WithMeterProvider(metric.NewMeterProvider()))
+		span.End()
+
+		a.Empty(recorder.Ended()[0].Attributes())
+	})
+}
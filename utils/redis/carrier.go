@@ -1,33 +1,18 @@
 package redis
 
-type EventCarrier struct {
-	headers map[string]string
-}
-
-func (e *EventCarrier) Set(key string, value string) {
-	e.headers[key] = value
-}
+import (
+	"github.com/weeb-vip/go-tracing-lib/utils/carrier"
+)
 
-func (e *EventCarrier) Get(key string) string {
-	return e.headers[key]
-}
+// EventCarrier carries trace context in message headers
+type EventCarrier = carrier.Map[string]
 
-func (e *EventCarrier) Keys() []string {
-	keys := make([]string, 0, len(e.headers))
-	for k := range e.headers {
-		keys = append(keys, k)
-	}
-	return keys
-}
+// NewEventCarrier creates a carrier over headers, or over new headers when nil
 func NewEventCarrier(headers *map[string]string) *EventCarrier {
 	if headers != nil {
-		return &EventCarrier{
-			headers: *headers,
-		}
-	}
-	return &EventCarrier{
-		headers: make(map[string]string),
+		return carrier.NewStringMap(*headers)
 	}
+	return carrier.NewStringMap(nil)
 }
 
 type RedisMessage[T any] interface {
@@ -2,11 +2,22 @@ package redis
 
 import (
 	"context"
-	"go.opentelemetry.io/otel"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/weeb-vip/go-tracing-lib/internal/queuelatency"
+	"github.com/weeb-vip/go-tracing-lib/utils/carrier"
 )
 
-func ExtractTraceContext[T any](ctx context.Context, message RedisMessage[T]) context.Context {
-	h := &EventCarrier{headers: message.Headers()}
-	ctx = otel.GetTextMapPropagator().Extract(ctx, h)
-	return ctx
+// ExtractTraceContext returns ctx with the trace context of message. Messages
+// stamped with WithPublishTimestamp have their queue latency recorded in the
+// histogram; there is no consumer span yet to set it on, see StartBatchSpan.
+func ExtractTraceContext[T any](ctx context.Context, message RedisMessage[T], opts ...Option) context.Context {
+	c := newConfig(opts)
+	publishedAt := queuelatency.Parse(message.Headers()[queuelatency.Header])
+	// the span of ctx belongs to the caller, the clock skew flag is not set on it
+	c.latency().Record(ctx, trace.SpanFromContext(context.Background()), publishedAt, semconv.MessagingSystemKey.String("redis"))
+
+	return c.propagator.Extract(ctx, carrier.NewStringMap(message.Headers()))
 }
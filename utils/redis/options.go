@@ -0,0 +1,109 @@
+package redis
+
+import (
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/weeb-vip/go-tracing-lib/internal/batchlinks"
+	"github.com/weeb-vip/go-tracing-lib/internal/queuelatency"
+	"github.com/weeb-vip/go-tracing-lib/tracing/outbound"
+)
+
+const instrumentationName = "github.com/weeb-vip/go-tracing-lib/utils/redis"
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	propagator     propagation.TextMapPropagator
+	policy         *outbound.Policy
+	maxLinks       int
+	timestamp      bool
+	meterProvider  metric.MeterProvider
+}
+
+// Option configures WrapPublishMessage, ExtractTraceContext and StartBatchSpan
+type Option func(*config)
+
+// WithTracerProvider sets the tracer provider, defaults to the global provider
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = provider
+	}
+}
+
+// WithPropagator sets the propagator used for the message headers, defaults to the global propagator
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagator = propagator
+	}
+}
+
+// WithPropagationPolicy only propagates the trace context the policy allows.
+// WrapPublishMessage does not know the destination and applies the default
+// rule, e.g. to keep baggage away from external consumers
+func WithPropagationPolicy(policy *outbound.Policy) Option {
+	return func(c *config) {
+		c.policy = policy
+	}
+}
+
+// WithMaxLinks caps the producer links of the batch span, defaults to 128
+func WithMaxLinks(max int) Option {
+	return func(c *config) {
+		c.maxLinks = max
+	}
+}
+
+// WithPublishTimestamp stamps published messages with their publish time, in
+// Unix milliseconds, so that consumers record how long they were queued
+func WithPublishTimestamp() Option {
+	return func(c *config) {
+		c.timestamp = true
+	}
+}
+
+// WithMeterProvider sets the meter provider of the queue latency histogram, defaults to the global provider
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = provider
+	}
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{
+		tracerProvider: otel.GetTracerProvider(),
+		propagator:     otel.GetTextMapPropagator(),
+		maxLinks:       batchlinks.DefaultMax,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// outbound returns the propagator injecting the trace context allowed by the default rule of the policy
+func (c *config) outbound() propagation.TextMapPropagator {
+	if c.policy == nil {
+		return c.propagator
+	}
+	return c.policy.Propagator("", c.propagator)
+}
+
+// defaultLatency is the queue latency recorder of the global meter provider,
+// which forwards to the provider set later with otel.SetMeterProvider
+var defaultLatency = sync.OnceValue(func() *queuelatency.Recorder {
+	return queuelatency.New(otel.GetMeterProvider(), instrumentationName)
+})
+
+// latency returns the queue latency recorder of the meter provider. The config
+// is built on every call, a provider set with WithMeterProvider returns the
+// histogram it already created for the scope.
+func (c *config) latency() *queuelatency.Recorder {
+	if c.meterProvider == nil {
+		return defaultLatency()
+	}
+	return queuelatency.New(c.meterProvider, instrumentationName)
+}
@@ -2,14 +2,22 @@ package redis
 
 import (
 	"context"
-	"go.opentelemetry.io/otel"
+	"strconv"
+
+	"github.com/weeb-vip/go-tracing-lib/internal/queuelatency"
 )
 
-func WrapPublishMessage[T any](ctx context.Context, msg RedisMessage[T]) RedisMessage[T] {
+// WrapPublishMessage adds the trace context of ctx to the message headers, and
+// its publish time with WithPublishTimestamp
+func WrapPublishMessage[T any](ctx context.Context, msg RedisMessage[T], opts ...Option) RedisMessage[T] {
+	c := newConfig(opts)
 	// Add trace context to message headers
 	headers := NewEventCarrier(nil)
-	otel.GetTextMapPropagator().Inject(ctx, headers)
+	c.outbound().Inject(ctx, headers)
+	if c.timestamp {
+		headers.Set(queuelatency.Header, strconv.FormatInt(queuelatency.Stamp(), 10))
+	}
 	// add on to existing headers
-	msg.SetHeaders(headers.headers)
+	msg.SetHeaders(headers.Headers())
 	return msg
 }
@@ -0,0 +1,107 @@
+package redisv8
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/weeb-vip/go-tracing-lib/internal/rediscmd"
+	"github.com/weeb-vip/go-tracing-lib/providers"
+)
+
+// Hook is a go-redis v8 hook creating a client span per command and pipeline
+//
+//	rdb := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+//	rdb.AddHook(redisv8.NewHook())
+type Hook struct {
+	config *rediscmd.Config
+}
+
+var _ redis.Hook = &Hook{}
+
+// NewHook creates a hook configured with options
+func NewHook(opts ...Option) *Hook {
+	return &Hook{config: rediscmd.NewConfig(opts)}
+}
+
+type spanKey struct{}
+
+// pendingSpan carries the span from the Before to the After callback
+type pendingSpan struct {
+	span      trace.Span
+	statement string
+}
+
+func (h *Hook) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	ctx, span, statement := h.config.StartCommand(ctx, cmd.FullName(), cmd.Args())
+	return context.WithValue(ctx, spanKey{}, &pendingSpan{span: span, statement: statement}), nil
+}
+
+func (h *Hook) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	if pending, ok := ctx.Value(spanKey{}).(*pendingSpan); ok {
+		h.config.Finish(ctx, pending.span, pending.statement, commandError(cmd.Err()))
+	}
+	return nil
+}
+
+func (h *Hook) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	names := make([]string, len(cmds))
+	args := make([][]any, len(cmds))
+	for i, cmd := range cmds {
+		names[i] = cmd.FullName()
+		args[i] = cmd.Args()
+	}
+	ctx, span, statement := h.config.StartPipeline(ctx, names, args)
+	return context.WithValue(ctx, spanKey{}, &pendingSpan{span: span, statement: statement}), nil
+}
+
+func (h *Hook) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	pending, ok := ctx.Value(spanKey{}).(*pendingSpan)
+	if !ok {
+		return nil
+	}
+
+	var err error
+	for _, cmd := range cmds {
+		if err = commandError(cmd.Err()); err != nil {
+			break
+		}
+	}
+	h.config.Finish(ctx, pending.span, pending.statement, err)
+	return nil
+}
+
+// commandError drops redis.Nil, a missing key is not a failure
+func commandError(err error) error {
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	return err
+}
+
+// Option configures the hook
+type Option = rediscmd.Option
+
+// WithTracerProvider sets the tracer provider, defaults to the global provider
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return rediscmd.WithTracerProvider(provider)
+}
+
+// WithAttributes adds attributes to every span, e.g. the database index
+func WithAttributes(attrs ...attribute.KeyValue) Option {
+	return rediscmd.WithAttributes(attrs...)
+}
+
+// WithRawStatements records command arguments as sent instead of replacing values with ?
+func WithRawStatements() Option {
+	return rediscmd.WithRawStatements()
+}
+
+// WithLogging logs every command at debug level, and failures at error level,
+// with the trace-aware logger of the command context
+func WithLogging(formats ...providers.LogFormat) Option {
+	return rediscmd.WithLogging(formats...)
+}
@@ -0,0 +1,45 @@
+package redisv8_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/weeb-vip/go-tracing-lib/utils/redis/redisv8"
+)
+
+func TestHook(t *testing.T) {
+	t.Run("Should trace commands and pipelines", func(t *testing.T) {
+		a := assert.New(t)
+		server := miniredis.RunT(t)
+		recorder := tracetest.NewSpanRecorder()
+		client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+		client.AddHook(redisv8.NewHook(redisv8.WithTracerProvider(trace.NewTracerProvider(trace.WithSpanProcessor(recorder)))))
+		defer client.Close()
+		ctx := context.Background()
+
+		a.NoError(client.HSet(ctx, "user:1", "name", "alice").Err())
+		_, err := client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Get(ctx, "missing")
+			pipe.Del(ctx, "a", "b")
+			return nil
+		})
+		a.ErrorIs(err, redis.Nil)
+
+		spans := recorder.Ended()
+		a.Len(spans, 2)
+		a.Equal("hset", spans[0].Name())
+		a.Contains(spans[0].Attributes(), attribute.String("db.statement", "HSET user:1 ? ?"))
+
+		a.Equal("redis.pipeline", spans[1].Name())
+		a.Contains(spans[1].Attributes(), attribute.Int("db.redis.key_count", 3))
+		a.Equal(codes.Unset, spans[1].Status().Code)
+	})
+}
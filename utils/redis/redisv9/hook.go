@@ -0,0 +1,122 @@
+package redisv9
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/weeb-vip/go-tracing-lib/internal/rediscmd"
+	"github.com/weeb-vip/go-tracing-lib/providers"
+)
+
+// Hook is a go-redis v9 hook creating a client span per command, pipeline and dial
+//
+//	rdb := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+//	rdb.AddHook(redisv9.NewHook())
+type Hook struct {
+	config *rediscmd.Config
+}
+
+var _ redis.Hook = &Hook{}
+
+// NewHook creates a hook configured with options
+func NewHook(opts ...Option) *Hook {
+	return &Hook{config: rediscmd.NewConfig(opts)}
+}
+
+func (h *Hook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		ctx, span := h.config.TracerProvider.Tracer(rediscmd.InstrumentationName).Start(ctx, "redis.dial",
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(semconv.DBSystemRedis, semconv.NetTransportKey.String(network)),
+			trace.WithAttributes(peerAttributes(addr)...),
+		)
+
+		conn, err := next(ctx, network, addr)
+		h.config.Finish(ctx, span, "", err)
+		return conn, err
+	}
+}
+
+func (h *Hook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		ctx, span, statement := h.config.StartCommand(ctx, cmd.FullName(), cmd.Args())
+
+		err := next(ctx, cmd)
+		h.config.Finish(ctx, span, statement, commandError(err))
+		return err
+	}
+}
+
+func (h *Hook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		names := make([]string, len(cmds))
+		args := make([][]any, len(cmds))
+		for i, cmd := range cmds {
+			names[i] = cmd.FullName()
+			args[i] = cmd.Args()
+		}
+		ctx, span, statement := h.config.StartPipeline(ctx, names, args)
+
+		err := next(ctx, cmds)
+		if err == nil {
+			for _, cmd := range cmds {
+				if err = commandError(cmd.Err()); err != nil {
+					break
+				}
+			}
+		}
+		h.config.Finish(ctx, span, statement, commandError(err))
+		return err
+	}
+}
+
+// commandError drops redis.Nil, a missing key is not a failure
+func commandError(err error) error {
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	return err
+}
+
+func peerAttributes(addr string) []attribute.KeyValue {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil
+	}
+	attrs := []attribute.KeyValue{semconv.NetPeerName(host)}
+	if p, err := strconv.Atoi(port); err == nil {
+		attrs = append(attrs, semconv.NetPeerPort(p))
+	}
+	return attrs
+}
+
+// Option configures the hook
+type Option = rediscmd.Option
+
+// WithTracerProvider sets the tracer provider, defaults to the global provider
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return rediscmd.WithTracerProvider(provider)
+}
+
+// WithAttributes adds attributes to every span, e.g. the database index
+func WithAttributes(attrs ...attribute.KeyValue) Option {
+	return rediscmd.WithAttributes(attrs...)
+}
+
+// WithRawStatements records command arguments as sent instead of replacing values with ?
+func WithRawStatements() Option {
+	return rediscmd.WithRawStatements()
+}
+
+// WithLogging logs every command at debug level, and failures at error level,
+// with the trace-aware logger of the command context
+func WithLogging(formats ...providers.LogFormat) Option {
+	return rediscmd.WithLogging(formats...)
+}
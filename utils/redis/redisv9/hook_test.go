@@ -0,0 +1,78 @@
+package redisv9_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/weeb-vip/go-tracing-lib/utils/redis/redisv9"
+)
+
+func setup(t *testing.T) (*redis.Client, *tracetest.SpanRecorder) {
+	server := miniredis.RunT(t)
+	recorder := tracetest.NewSpanRecorder()
+
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	client.AddHook(redisv9.NewHook(redisv9.WithTracerProvider(trace.NewTracerProvider(trace.WithSpanProcessor(recorder)))))
+	t.Cleanup(func() { client.Close() })
+	return client, recorder
+}
+
+func spanNamed(recorder *tracetest.SpanRecorder, name string) trace.ReadOnlySpan {
+	for _, span := range recorder.Ended() {
+		if span.Name() == name {
+			return span
+		}
+	}
+	return nil
+}
+
+func TestHook(t *testing.T) {
+	t.Run("Should trace commands with sanitized statements", func(t *testing.T) {
+		a := assert.New(t)
+		client, recorder := setup(t)
+		ctx := context.Background()
+
+		a.NoError(client.Set(ctx, "user:1", "secret", 0).Err())
+		a.NoError(client.MGet(ctx, "user:1", "user:2").Err())
+		a.ErrorIs(client.Get(ctx, "missing").Err(), redis.Nil)
+
+		set := spanNamed(recorder, "set")
+		a.Contains(set.Attributes(), attribute.String("db.system", "redis"))
+		a.Contains(set.Attributes(), attribute.String("db.statement", "SET user:1 ?"))
+		a.Contains(set.Attributes(), attribute.Int("db.redis.key_count", 1))
+
+		mget := spanNamed(recorder, "mget")
+		a.Contains(mget.Attributes(), attribute.String("db.statement", "MGET user:1 user:2"))
+		a.Contains(mget.Attributes(), attribute.Int("db.redis.key_count", 2))
+
+		a.Equal(codes.Unset, spanNamed(recorder, "get").Status().Code)
+		a.NotNil(spanNamed(recorder, "redis.dial"))
+	})
+
+	t.Run("Should trace pipelines and errors", func(t *testing.T) {
+		a := assert.New(t)
+		client, recorder := setup(t)
+		ctx := context.Background()
+
+		_, err := client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, "counter", "not a number", 0)
+			pipe.Incr(ctx, "counter")
+			return nil
+		})
+		a.Error(err)
+
+		span := spanNamed(recorder, "redis.pipeline")
+		a.Contains(span.Attributes(), attribute.Int("db.redis.num_cmd", 2))
+		a.Contains(span.Attributes(), attribute.String("db.operation", "set incr"))
+		a.Contains(span.Attributes(), attribute.String("db.statement", "SET counter ?\nINCR counter"))
+		a.Equal(codes.Error, span.Status().Code)
+	})
+}
@@ -0,0 +1,127 @@
+package streams
+
+import (
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/weeb-vip/go-tracing-lib/internal/batchlinks"
+	"github.com/weeb-vip/go-tracing-lib/logger"
+	"github.com/weeb-vip/go-tracing-lib/providers"
+	"github.com/weeb-vip/go-tracing-lib/tracing/outbound"
+)
+
+const instrumentationName = "github.com/weeb-vip/go-tracing-lib/utils/redis/streams"
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	propagator     propagation.TextMapPropagator
+	policy         *outbound.Policy
+	logFormats     []providers.LogFormat
+	batchSize      int64
+	block          time.Duration
+	reclaimIdle    time.Duration
+	maxLinks       int
+	meterProvider  metric.MeterProvider
+}
+
+// Option configures a streams client
+type Option func(*config)
+
+// WithTracerProvider sets the tracer provider, defaults to the global provider
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = provider
+	}
+}
+
+// WithPropagator sets the propagator used for the stream fields, defaults to the global propagator
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagator = propagator
+	}
+}
+
+// WithPropagationPolicy only propagates to each stream the trace context its
+// rule allows, e.g. to keep baggage away from external consumers
+func WithPropagationPolicy(policy *outbound.Policy) Option {
+	return func(c *config) {
+		c.policy = policy
+	}
+}
+
+// WithLogFormats selects the trace identifier formats of the consumer logger,
+// defaults to logger.DefaultTraceFormats
+func WithLogFormats(formats ...providers.LogFormat) Option {
+	return func(c *config) {
+		c.logFormats = formats
+	}
+}
+
+// WithBatchSize sets how many messages are read at once, defaults to 10
+func WithBatchSize(size int64) Option {
+	return func(c *config) {
+		c.batchSize = size
+	}
+}
+
+// WithBlock sets how long a read waits for new messages before checking for
+// cancellation and reclaimable messages, defaults to 5 seconds
+func WithBlock(block time.Duration) Option {
+	return func(c *config) {
+		c.block = block
+	}
+}
+
+// WithReclaim claims messages left pending by any consumer of the group for
+// longer than minIdle, e.g. because their handler failed or the consumer died
+func WithReclaim(minIdle time.Duration) Option {
+	return func(c *config) {
+		c.reclaimIdle = minIdle
+	}
+}
+
+// WithMaxLinks caps the producer links of a batch span, defaults to 128
+func WithMaxLinks(max int) Option {
+	return func(c *config) {
+		c.maxLinks = max
+	}
+}
+
+// WithMeterProvider sets the meter provider of the queue latency histogram, defaults to the global provider
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = provider
+	}
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{
+		tracerProvider: otel.GetTracerProvider(),
+		propagator:     otel.GetTextMapPropagator(),
+		logFormats:     logger.DefaultTraceFormats,
+		batchSize:      10,
+		block:          5 * time.Second,
+		maxLinks:       batchlinks.DefaultMax,
+		meterProvider:  otel.GetMeterProvider(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *config) tracer() trace.Tracer {
+	return c.tracerProvider.Tracer(instrumentationName)
+}
+
+// outbound returns the propagator injecting the trace context sent to destination
+func (c *config) outbound(destination string) propagation.TextMapPropagator {
+	if c.policy == nil {
+		return c.propagator
+	}
+	return c.policy.Propagator(destination, c.propagator)
+}
@@ -0,0 +1,359 @@
+package streams
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/weeb-vip/go-tracing-lib/internal/batchlinks"
+	"github.com/weeb-vip/go-tracing-lib/internal/queuelatency"
+	"github.com/weeb-vip/go-tracing-lib/logger"
+)
+
+const (
+	ConsumerGroupKey = attribute.Key("messaging.redis.consumer_group")
+	ReclaimedKey     = attribute.Key("messaging.redis.reclaimed")
+)
+
+// ErrStreamDoesNotExist is returned when publishing to a stream that was not created
+var ErrStreamDoesNotExist = errors.New("stream does not exist")
+
+// Message is a stream entry, Values excludes the trace context fields
+type Message struct {
+	Stream string
+	ID     string
+	Values map[string]any
+}
+
+// Handler processes a message within its consumer span. Messages whose
+// handler fails are left pending, see WithReclaim.
+type Handler func(ctx context.Context, message Message) error
+
+// BatchHandler processes messages within a single consumer span. The messages
+// of a failed batch are left pending, see WithReclaim.
+type BatchHandler func(ctx context.Context, messages []Message) error
+
+// Client publishes to and consumes Redis streams, carrying the trace context in the stream fields
+type Client struct {
+	client  redis.UniversalClient
+	config  *config
+	latency *queuelatency.Recorder
+}
+
+// NewClient creates a streams client over a go-redis v9 client
+func NewClient(client redis.UniversalClient, opts ...Option) *Client {
+	c := newConfig(opts)
+	return &Client{client: client, config: c, latency: queuelatency.New(c.meterProvider, instrumentationName)}
+}
+
+// CreateGroup creates the stream if needed and a consumer group reading its new messages,
+// an existing group is not an error
+//
+// For more information on XGROUP CREATE, please see https://redis.io/commands/xgroup-create/
+func (c *Client) CreateGroup(ctx context.Context, stream, group string) error {
+	err := c.client.XGroupCreateMkStream(ctx, stream, group, "$").Err()
+	if err != nil && !redis.HasErrorPrefix(err, "BUSYGROUP") {
+		return fmt.Errorf("failed to create consumer group: %w", err)
+	}
+	return nil
+}
+
+// Publish adds values to stream in a producer span and returns the ID of the entry.
+// It fails with ErrStreamDoesNotExist if the stream was not created.
+//
+// For more information on XADD, please see https://redis.io/commands/xadd/
+func (c *Client) Publish(ctx context.Context, stream string, values map[string]any) (string, error) {
+	ctx, span := c.config.tracer().Start(ctx, stream+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKey.String("redis"),
+			semconv.MessagingOperationPublish,
+			semconv.MessagingDestinationName(stream),
+		),
+	)
+	defer span.End()
+
+	fields := make(map[string]any, len(values)+2)
+	for k, v := range values {
+		fields[k] = v
+	}
+	c.config.outbound(stream).Inject(ctx, fieldCarrier(fields))
+
+	id, err := c.client.XAdd(ctx, &redis.XAddArgs{
+		Stream:     stream,
+		NoMkStream: true,
+		ID:         "*",
+		Values:     fields,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		err = ErrStreamDoesNotExist
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return "", fmt.Errorf("failed to publish to stream: %w", err)
+	}
+	span.SetAttributes(semconv.MessagingMessageID(id))
+	return id, nil
+}
+
+// Consume creates the consumer group if needed, processes the messages left
+// pending for consumer and then listens for new messages until ctx is done.
+// Each message is handled in a consumer span continuing the publisher's trace
+// and acknowledged once its handler succeeds.
+//
+// For more information on XREADGROUP, please see https://redis.io/commands/xreadgroup/
+func (c *Client) Consume(ctx context.Context, stream, group, consumer string, handler Handler) error {
+	return c.consume(ctx, stream, group, consumer, func(messages []redis.XMessage, reclaimed bool) error {
+		for _, message := range messages {
+			if err := c.handle(ctx, stream, group, consumer, message, reclaimed, handler); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ConsumeBatch consumes like Consume but handles each read of up to
+// WithBatchSize messages in a single consumer span linked to the span of
+// every publisher. The messages are acknowledged once handler succeeds.
+func (c *Client) ConsumeBatch(ctx context.Context, stream, group, consumer string, handler BatchHandler) error {
+	return c.consume(ctx, stream, group, consumer, func(messages []redis.XMessage, reclaimed bool) error {
+		return c.handleBatch(ctx, stream, group, consumer, messages, reclaimed, handler)
+	})
+}
+
+// consume reads the pending, reclaimable and new messages of stream and passes them to process
+func (c *Client) consume(ctx context.Context, stream, group, consumer string, process func([]redis.XMessage, bool) error) error {
+	if err := c.CreateGroup(ctx, stream, group); err != nil {
+		return err
+	}
+
+	// a last ID of 0 reads the pending entries of this consumer
+	if err := c.read(ctx, stream, group, consumer, "0", process); err != nil {
+		return err
+	}
+
+	for ctx.Err() == nil {
+		if c.config.reclaimIdle > 0 {
+			if err := c.reclaim(ctx, stream, group, consumer, process); err != nil {
+				return err
+			}
+		}
+		if err := c.read(ctx, stream, group, consumer, ">", process); err != nil {
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
+func (c *Client) read(ctx context.Context, stream, group, consumer, id string, process func([]redis.XMessage, bool) error) error {
+	args := &redis.XReadGroupArgs{
+		Streams:  []string{stream, id},
+		Group:    group,
+		Consumer: consumer,
+		Count:    c.config.batchSize,
+		Block:    c.config.block,
+	}
+	if id != ">" {
+		// pending entries are returned at once
+		args.Block = -1
+	}
+
+	for {
+		results, err := c.client.XReadGroup(ctx, args).Result()
+		if errors.Is(err, redis.Nil) || (err != nil && ctx.Err() != nil) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read messages: %w", err)
+		}
+
+		var messages []redis.XMessage
+		for _, result := range results {
+			messages = append(messages, result.Messages...)
+		}
+		if len(messages) > 0 {
+			if err := process(messages, false); err != nil {
+				return err
+			}
+		}
+		// new messages are read one batch at a time, pending ones until none is left
+		if id == ">" || len(messages) == 0 {
+			return nil
+		}
+		args.Streams[1] = messages[len(messages)-1].ID
+	}
+}
+
+// reclaim claims and processes the messages left pending for too long
+//
+// For more information on XAUTOCLAIM, please see https://redis.io/commands/xautoclaim/
+func (c *Client) reclaim(ctx context.Context, stream, group, consumer string, process func([]redis.XMessage, bool) error) error {
+	start := "0-0"
+	for {
+		messages, next, err := c.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+			Stream:   stream,
+			Group:    group,
+			Consumer: consumer,
+			MinIdle:  c.config.reclaimIdle,
+			Start:    start,
+			Count:    c.config.batchSize,
+		}).Result()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to reclaim messages: %w", err)
+		}
+		if len(messages) > 0 {
+			if err := process(messages, true); err != nil {
+				return err
+			}
+		}
+		if next == "0-0" {
+			return nil
+		}
+		start = next
+	}
+}
+
+// handle runs handler for a message, only failures to acknowledge it are returned
+func (c *Client) handle(ctx context.Context, stream, group, consumer string, message redis.XMessage, reclaimed bool, handler Handler) error {
+	ctx = c.config.propagator.Extract(ctx, fieldCarrier(message.Values))
+	ctx, span := c.config.tracer().Start(ctx, stream+" process",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(consumerAttributes(stream, group, consumer, reclaimed)...),
+		trace.WithAttributes(semconv.MessagingMessageID(message.ID)),
+	)
+	defer span.End()
+	if latency, ok := c.recordLatency(ctx, span, stream, message); ok {
+		queuelatency.SetAttribute(span, latency)
+	}
+
+	ctx = logger.ContextWithTraceFormats(ctx, c.config.logFormats...)
+	l := logger.FromContext(ctx)
+
+	err := handler(ctx, Message{Stream: stream, ID: message.ID, Values: c.payload(message.Values)})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		l.Error().Err(err).Msgf("failed to consume message (%s)", message.ID)
+		return nil
+	}
+
+	if err := c.client.XAck(ctx, stream, group, message.ID).Err(); err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to ACK message (%s): %w", message.ID, err)
+	}
+	return nil
+}
+
+// handleBatch runs handler for messages in a span linked to their publishers,
+// only failures to acknowledge them are returned
+func (c *Client) handleBatch(ctx context.Context, stream, group, consumer string, messages []redis.XMessage, reclaimed bool, handler BatchHandler) error {
+	links := batchlinks.New(c.config.maxLinks)
+	batch := make([]Message, len(messages))
+	ids := make([]string, len(messages))
+	for i, message := range messages {
+		producer := trace.SpanContextFromContext(c.config.propagator.Extract(ctx, fieldCarrier(message.Values)))
+		links.Add(producer, semconv.MessagingMessageID(message.ID))
+		batch[i] = Message{Stream: stream, ID: message.ID, Values: c.payload(message.Values)}
+		ids[i] = message.ID
+	}
+
+	startOpts := append([]trace.SpanStartOption{
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(consumerAttributes(stream, group, consumer, reclaimed)...),
+	}, links.StartOptions()...)
+	ctx, span := c.config.tracer().Start(ctx, stream+" process", startOpts...)
+	defer span.End()
+
+	times := make([]time.Time, len(messages))
+	for i, message := range messages {
+		times[i] = queuelatency.FromStreamID(message.ID)
+	}
+	c.latency.RecordLongest(ctx, span, times, latencyAttributes(stream)...)
+
+	ctx = logger.ContextWithTraceFormats(ctx, c.config.logFormats...)
+	l := logger.FromContext(ctx)
+
+	if err := handler(ctx, batch); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		l.Error().Err(err).Msgf("failed to consume %d messages", len(batch))
+		return nil
+	}
+
+	if err := c.client.XAck(ctx, stream, group, ids...).Err(); err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to ACK %d messages: %w", len(ids), err)
+	}
+	return nil
+}
+
+// recordLatency records the queue latency of message from the time in its
+// entry ID, reclaimed messages include the time they were pending
+func (c *Client) recordLatency(ctx context.Context, span trace.Span, stream string, message redis.XMessage) (time.Duration, bool) {
+	return c.latency.Record(ctx, span, queuelatency.FromStreamID(message.ID), latencyAttributes(stream)...)
+}
+
+// latencyAttributes are the attributes of the queue latency histogram
+func latencyAttributes(stream string) []attribute.KeyValue {
+	return []attribute.KeyValue{semconv.MessagingSystemKey.String("redis"), semconv.MessagingSourceName(stream)}
+}
+
+func consumerAttributes(stream, group, consumer string, reclaimed bool) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		semconv.MessagingSystemKey.String("redis"),
+		semconv.MessagingOperationProcess,
+		semconv.MessagingSourceName(stream),
+		semconv.MessagingConsumerID(consumer),
+		ConsumerGroupKey.String(group),
+		ReclaimedKey.Bool(reclaimed),
+	}
+}
+
+// payload returns values without the fields set by the propagator
+func (c *Client) payload(values map[string]any) map[string]any {
+	payload := make(map[string]any, len(values))
+	for k, v := range values {
+		payload[k] = v
+	}
+	for _, field := range c.config.propagator.Fields() {
+		delete(payload, field)
+	}
+	return payload
+}
+
+// fieldCarrier adapts stream fields to a propagation.TextMapCarrier
+type fieldCarrier map[string]any
+
+var _ propagation.TextMapCarrier = fieldCarrier{}
+
+func (f fieldCarrier) Get(key string) string {
+	if value, ok := f[key].(string); ok {
+		return value
+	}
+	return ""
+}
+
+func (f fieldCarrier) Set(key string, value string) {
+	f[key] = value
+}
+
+func (f fieldCarrier) Keys() []string {
+	keys := make([]string, 0, len(f))
+	for k := range f {
+		keys = append(keys, k)
+	}
+	return keys
+}
@@ -0,0 +1,163 @@
+package streams_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+
+	"github.com/weeb-vip/go-tracing-lib/utils/redis/streams"
+)
+
+func setup(t *testing.T, opts ...streams.Option) (*streams.Client, *tracetest.SpanRecorder) {
+	server := miniredis.RunT(t)
+	recorder := tracetest.NewSpanRecorder()
+
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return streams.NewClient(client, append([]streams.Option{
+		streams.WithTracerProvider(trace.NewTracerProvider(trace.WithSpanProcessor(recorder))),
+		streams.WithPropagator(propagation.TraceContext{}),
+		streams.WithBlock(10 * time.Millisecond),
+	}, opts...)...), recorder
+}
+
+// consume runs Consume until handler was called calls times
+func consume(t *testing.T, client *streams.Client, calls int, handler streams.Handler) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := client.Consume(ctx, "events", "workers", "worker-1", func(ctx context.Context, message streams.Message) error {
+		err := handler(ctx, message)
+		if calls--; calls == 0 {
+			cancel()
+		}
+		return err
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func spansNamed(recorder *tracetest.SpanRecorder, name string) []trace.ReadOnlySpan {
+	var spans []trace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		if span.Name() == name {
+			spans = append(spans, span)
+		}
+	}
+	return spans
+}
+
+func TestClient(t *testing.T) {
+	t.Run("Should fail to publish to a missing stream", func(t *testing.T) {
+		a := assert.New(t)
+		client, recorder := setup(t)
+
+		_, err := client.Publish(context.Background(), "events", map[string]any{"name": "created"})
+
+		a.ErrorIs(err, streams.ErrStreamDoesNotExist)
+		a.Equal(codes.Error, spansNamed(recorder, "events publish")[0].Status().Code)
+	})
+
+	t.Run("Should continue the publisher's trace when consuming", func(t *testing.T) {
+		a := assert.New(t)
+		client, recorder := setup(t)
+		ctx := context.Background()
+		a.NoError(client.CreateGroup(ctx, "events", "workers"))
+		a.NoError(client.CreateGroup(ctx, "events", "workers"))
+
+		id, err := client.Publish(ctx, "events", map[string]any{"name": "created"})
+		a.NoError(err)
+
+		var received streams.Message
+		consume(t, client, 1, func(ctx context.Context, message streams.Message) error {
+			received = message
+			return nil
+		})
+
+		a.Equal(id, received.ID)
+		a.Equal(map[string]any{"name": "created"}, received.Values)
+
+		producer := spansNamed(recorder, "events publish")[0]
+		a.Equal(oteltrace.SpanKindProducer, producer.SpanKind())
+		a.Contains(producer.Attributes(), attribute.String("messaging.message.id", id))
+
+		consumer := spansNamed(recorder, "events process")[0]
+		a.Equal(oteltrace.SpanKindConsumer, consumer.SpanKind())
+		a.Equal(producer.SpanContext().SpanID(), consumer.Parent().SpanID())
+		a.Contains(consumer.Attributes(), attribute.String("messaging.redis.consumer_group", "workers"))
+		a.Contains(consumer.Attributes(), attribute.Bool("messaging.redis.reclaimed", false))
+		a.True(hasAttribute(consumer.Attributes(), "messaging.queue_latency"))
+	})
+
+	t.Run("Should leave failed messages pending and reclaim them", func(t *testing.T) {
+		a := assert.New(t)
+		client, recorder := setup(t, streams.WithReclaim(time.Millisecond))
+		ctx := context.Background()
+		a.NoError(client.CreateGroup(ctx, "events", "workers"))
+		_, err := client.Publish(ctx, "events", map[string]any{"name": "created"})
+		a.NoError(err)
+
+		attempts := 0
+		consume(t, client, 2, func(ctx context.Context, message streams.Message) error {
+			if attempts++; attempts == 1 {
+				return errors.New("boom")
+			}
+			return nil
+		})
+
+		spans := spansNamed(recorder, "events process")
+		a.Len(spans, 2)
+		a.Equal(codes.Error, spans[0].Status().Code)
+		a.Contains(spans[1].Attributes(), attribute.Bool("messaging.redis.reclaimed", true))
+		a.Equal(codes.Unset, spans[1].Status().Code)
+	})
+}
+
+func TestClientBatch(t *testing.T) {
+	t.Run("Should consume batches in one span linked to every publisher", func(t *testing.T) {
+		a := assert.New(t)
+		client, recorder := setup(t)
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		a.NoError(client.CreateGroup(ctx, "events", "workers"))
+		for _, name := range []string{"created", "updated"} {
+			_, err := client.Publish(ctx, "events", map[string]any{"name": name})
+			a.NoError(err)
+		}
+
+		var received []streams.Message
+		err := client.ConsumeBatch(ctx, "events", "workers", "worker-1", func(ctx context.Context, messages []streams.Message) error {
+			received = messages
+			cancel()
+			return nil
+		})
+
+		a.ErrorIs(err, context.Canceled)
+		a.Len(received, 2)
+		producers := spansNamed(recorder, "events publish")
+		batch := spansNamed(recorder, "events process")[0]
+		a.Len(batch.Links(), 2)
+		a.Equal(producers[1].SpanContext().SpanID(), batch.Links()[1].SpanContext.SpanID())
+		a.Contains(batch.Links()[0].Attributes, attribute.String("messaging.message.id", received[0].ID))
+		a.Contains(batch.Attributes(), attribute.Int("messaging.batch.message_count", 2))
+	})
+}
+
+func hasAttribute(attrs []attribute.KeyValue, key attribute.Key) bool {
+	for _, attr := range attrs {
+		if attr.Key == key {
+			return true
+		}
+	}
+	return false
+}
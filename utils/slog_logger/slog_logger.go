@@ -0,0 +1,92 @@
+package slog_logger
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/weeb-vip/go-tracing-lib/internal/logfields"
+	"github.com/weeb-vip/go-tracing-lib/logger"
+	"github.com/weeb-vip/go-tracing-lib/providers"
+)
+
+type ctxKey struct{}
+
+// Option configures the handler
+type Option func(*Handler)
+
+// WithFormats selects the trace identifier formats, defaults to logger.DefaultTraceFormats
+func WithFormats(formats ...providers.LogFormat) Option {
+	return func(h *Handler) {
+		h.formats = formats
+	}
+}
+
+// Handler is a slog.Handler that adds the trace and span IDs of the record's context
+type Handler struct {
+	handler slog.Handler
+	formats []providers.LogFormat
+}
+
+// NewHandler wraps handler so records logged with a context carry its trace identifiers
+func NewHandler(handler slog.Handler, opts ...Option) *Handler {
+	h := &Handler{
+		handler: handler,
+		formats: logger.DefaultTraceFormats,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// New returns a logger using a Handler around handler
+func New(handler slog.Handler, opts ...Option) *slog.Logger {
+	return slog.New(NewHandler(handler, opts...))
+}
+
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *Handler) Handle(ctx context.Context, record slog.Record) error {
+	fields := logfields.FromContext(ctx, h.formats)
+	if len(fields) > 0 {
+		record = record.Clone()
+		for _, field := range fields {
+			record.AddAttrs(slog.String(field.Key, field.Value))
+		}
+	}
+	return h.handler.Handle(ctx, record)
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &Handler{handler: h.handler.WithAttrs(attrs), formats: h.formats}
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{handler: h.handler.WithGroup(name), formats: h.formats}
+}
+
+// FromCtx returns the Logger associated with the ctx. If no logger
+// is associated, slog.Default is returned.
+func FromCtx(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(ctxKey{}).(*slog.Logger); ok {
+		return l
+	}
+	return slog.Default()
+}
+
+// FromContext is an alias for FromCtx
+func FromContext(ctx context.Context) *slog.Logger {
+	return FromCtx(ctx)
+}
+
+// WithCtx returns a copy of ctx with the Logger attached.
+func WithCtx(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, l)
+}
+
+// WithContext returns a copy of ctx with the Logger attached.
+func WithContext(l *slog.Logger, ctx context.Context) context.Context {
+	return WithCtx(ctx, l)
+}
@@ -0,0 +1,36 @@
+package slog_logger_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/weeb-vip/go-tracing-lib/providers"
+	"github.com/weeb-vip/go-tracing-lib/utils/slog_logger"
+)
+
+func TestHandler(t *testing.T) {
+	t.Run("Should add trace identifiers from the record context", func(t *testing.T) {
+		a := assert.New(t)
+		buf := &bytes.Buffer{}
+		l := slog_logger.New(slog.NewJSONHandler(buf, nil), slog_logger.WithFormats(providers.LogFormatGrafana))
+
+		ctx, span := trace.NewTracerProvider().Tracer("test").Start(context.Background(), "test")
+		defer span.End()
+		ctx = slog_logger.WithContext(l.With("component", "test"), ctx)
+
+		slog_logger.FromContext(ctx).InfoContext(ctx, "hello")
+
+		fields := map[string]any{}
+		a.NoError(json.Unmarshal(buf.Bytes(), &fields))
+		a.Equal("test", fields["component"])
+		a.Equal(span.SpanContext().TraceID().String(), fields["traceID"])
+		a.Equal(span.SpanContext().SpanID().String(), fields["spanID"])
+		a.NotContains(fields, "dd.trace_id")
+	})
+}
@@ -0,0 +1,176 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type tracedConn struct {
+	conn   driver.Conn
+	config *config
+}
+
+var _ interface {
+	driver.Conn
+	driver.ConnBeginTx
+	driver.ConnPrepareContext
+	driver.ExecerContext
+	driver.QueryerContext
+	driver.Pinger
+	driver.SessionResetter
+	driver.Validator
+	driver.NamedValueChecker
+} = &tracedConn{}
+
+func (c *tracedConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *tracedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	ctx, span := c.config.startSpan(ctx, methodPrepare, query)
+	defer span.End()
+
+	var stmt driver.Stmt
+	var err error
+	if cp, ok := c.conn.(driver.ConnPrepareContext); ok {
+		stmt, err = cp.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.conn.Prepare(query)
+	}
+	if err != nil {
+		recordError(span, err)
+		return nil, err
+	}
+	return &tracedStmt{stmt: stmt, conn: c, query: query, config: c.config}, nil
+}
+
+func (c *tracedConn) Close() error {
+	return c.conn.Close()
+}
+
+func (c *tracedConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *tracedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	spanCtx, span := c.config.startSpan(ctx, methodBegin, "")
+	defer span.End()
+
+	var tx driver.Tx
+	var err error
+	if cb, ok := c.conn.(driver.ConnBeginTx); ok {
+		tx, err = cb.BeginTx(spanCtx, opts)
+	} else {
+		tx, err = begin(c.conn, opts)
+	}
+	if err != nil {
+		recordError(span, err)
+		return nil, err
+	}
+	return &tracedTx{tx: tx, ctx: ctx, config: c.config}, nil
+}
+
+// begin starts a transaction on a driver without driver.ConnBeginTx, which
+// cannot honour options, with the errors database/sql returns for them
+func begin(conn driver.Conn, opts driver.TxOptions) (driver.Tx, error) {
+	if opts.Isolation != driver.IsolationLevel(sql.LevelDefault) {
+		return nil, errors.New("sql: driver does not support non-default isolation level")
+	}
+	if opts.ReadOnly {
+		return nil, errors.New("sql: driver does not support read-only transactions")
+	}
+	return conn.Begin()
+}
+
+// ExecContext returns driver.ErrSkip when the driver cannot execute directly,
+// database/sql then prepares the statement instead
+func (c *tracedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	ctx, span := c.config.startSpan(ctx, methodExec, query)
+	defer span.End()
+	if c.config.commenter {
+		query = commentStatement(ctx, c.config.propagator, query)
+	}
+
+	result, err := execer.ExecContext(ctx, query, args)
+	if err != nil {
+		recordError(span, err)
+		return nil, err
+	}
+	recordRowsAffected(span, result)
+	return result, nil
+}
+
+// QueryContext returns driver.ErrSkip when the driver cannot query directly,
+// database/sql then prepares the statement instead. The span ends when the rows are closed.
+func (c *tracedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	ctx, span := c.config.startSpan(ctx, methodQuery, query)
+	if c.config.commenter {
+		query = commentStatement(ctx, c.config.propagator, query)
+	}
+
+	rows, err := queryer.QueryContext(ctx, query, args)
+	if err != nil {
+		finishSpan(span, err)
+		return nil, err
+	}
+	return &tracedRows{rows: rows, span: span}, nil
+}
+
+func (c *tracedConn) Ping(ctx context.Context) error {
+	pinger, ok := c.conn.(driver.Pinger)
+	if !ok {
+		return nil
+	}
+	if !c.config.pingSpans {
+		return pinger.Ping(ctx)
+	}
+
+	ctx, span := c.config.startSpan(ctx, methodPing, "")
+	err := pinger.Ping(ctx)
+	finishSpan(span, err)
+	return err
+}
+
+func (c *tracedConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *tracedConn) IsValid() bool {
+	if validator, ok := c.conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+func (c *tracedConn) CheckNamedValue(value *driver.NamedValue) error {
+	if checker, ok := c.conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(value)
+	}
+	return driver.ErrSkip
+}
+
+func recordRowsAffected(span trace.Span, result driver.Result) {
+	if result == nil {
+		return
+	}
+	if affected, err := result.RowsAffected(); err == nil {
+		span.SetAttributes(RowsAffectedKey.Int64(affected))
+	}
+}
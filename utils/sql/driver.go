@@ -0,0 +1,163 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Open opens a database like sql.Open, with every call made through it traced
+func Open(driverName string, dataSourceName string, opts ...Option) (*sql.DB, error) {
+	db, err := sql.Open(driverName, dataSourceName)
+	if err != nil {
+		return nil, err
+	}
+	d := db.Driver()
+	if err := db.Close(); err != nil {
+		return nil, err
+	}
+
+	if dc, ok := d.(driver.DriverContext); ok {
+		connector, err := dc.OpenConnector(dataSourceName)
+		if err != nil {
+			return nil, err
+		}
+		return sql.OpenDB(WrapConnector(connector, opts...)), nil
+	}
+	return sql.OpenDB(WrapConnector(&dsnConnector{dsn: dataSourceName, driver: d}, opts...)), nil
+}
+
+// Register registers d wrapped with tracing under name, for use with sql.Open
+func Register(name string, d driver.Driver, opts ...Option) {
+	sql.Register(name, Wrap(d, opts...))
+}
+
+// Wrap returns a driver tracing the connections opened by d
+func Wrap(d driver.Driver, opts ...Option) driver.Driver {
+	return &tracedDriver{driver: d, config: newConfig(opts)}
+}
+
+// WrapConnector returns a connector tracing the connections opened by c
+func WrapConnector(c driver.Connector, opts ...Option) driver.Connector {
+	config := newConfig(opts)
+	return &tracedConnector{
+		connector: c,
+		driver:    &tracedDriver{driver: c.Driver(), config: config},
+		config:    config,
+	}
+}
+
+type tracedDriver struct {
+	driver driver.Driver
+	config *config
+}
+
+func (d *tracedDriver) Open(name string) (driver.Conn, error) {
+	c, err := d.driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &tracedConn{conn: c, config: d.config}, nil
+}
+
+func (d *tracedDriver) OpenConnector(name string) (driver.Connector, error) {
+	if dc, ok := d.driver.(driver.DriverContext); ok {
+		connector, err := dc.OpenConnector(name)
+		if err != nil {
+			return nil, err
+		}
+		return &tracedConnector{connector: connector, driver: d, config: d.config}, nil
+	}
+	return &tracedConnector{connector: &dsnConnector{dsn: name, driver: d.driver}, driver: d, config: d.config}, nil
+}
+
+type tracedConnector struct {
+	connector driver.Connector
+	driver    driver.Driver
+	config    *config
+}
+
+func (c *tracedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &tracedConn{conn: conn, config: c.config}, nil
+}
+
+func (c *tracedConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// dsnConnector is a driver.Connector for drivers without driver.DriverContext
+type dsnConnector struct {
+	dsn    string
+	driver driver.Driver
+}
+
+func (c *dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c *dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+const (
+	RowsAffectedKey = attribute.Key("db.rows_affected")
+	RowsReturnedKey = attribute.Key("db.rows_returned")
+
+	methodPrepare  = "sql.prepare"
+	methodExec     = "sql.exec"
+	methodQuery    = "sql.query"
+	methodBegin    = "sql.begin"
+	methodCommit   = "sql.commit"
+	methodRollback = "sql.rollback"
+	methodPing     = "sql.ping"
+)
+
+// startSpan starts a client span for method. Executed statements are named
+// after their operation, e.g. SELECT.
+func (c *config) startSpan(ctx context.Context, method string, query string) (context.Context, trace.Span) {
+	name := method
+	attrs := append([]attribute.KeyValue{}, c.attributes...)
+	if query != "" {
+		if op := operation(query); op != "" {
+			if method != methodPrepare {
+				name = op
+			}
+			attrs = append(attrs, semconv.DBOperation(op))
+		}
+		statement := query
+		if !c.rawStatements {
+			statement = normalizeStatement(query)
+		}
+		attrs = append(attrs, semconv.DBStatement(statement))
+	}
+
+	return c.tracerProvider.Tracer(instrumentationName).Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+}
+
+// finishSpan records err on span and ends it
+func finishSpan(span trace.Span, err error) {
+	recordError(span, err)
+	span.End()
+}
+
+func recordError(span trace.Span, err error) {
+	if err == nil || errors.Is(err, io.EOF) || errors.Is(err, driver.ErrSkip) {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
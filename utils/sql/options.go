@@ -0,0 +1,97 @@
+package sql
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/weeb-vip/go-tracing-lib/utils/sql"
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	propagator     propagation.TextMapPropagator
+	system         string
+	attributes     []attribute.KeyValue
+	rawStatements  bool
+	commenter      bool
+	pingSpans      bool
+}
+
+// Option configures the driver wrapper
+type Option func(*config)
+
+// WithTracerProvider sets the tracer provider, defaults to the global provider
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = provider
+	}
+}
+
+// WithPropagator sets the propagator used for SQL comments, defaults to W3C
+// trace context only so that baggage does not end up in database logs. Pass
+// otel.GetTextMapPropagator() to comment with the global propagator.
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagator = propagator
+	}
+}
+
+// WithDBSystem sets db.system, e.g. "postgresql" or "mysql", defaults to "other_sql"
+func WithDBSystem(system string) Option {
+	return func(c *config) {
+		c.system = system
+	}
+}
+
+// WithDBName sets db.name on every span
+func WithDBName(name string) Option {
+	return func(c *config) {
+		c.attributes = append(c.attributes, semconv.DBName(name))
+	}
+}
+
+// WithAttributes adds attributes to every span
+func WithAttributes(attrs ...attribute.KeyValue) Option {
+	return func(c *config) {
+		c.attributes = append(c.attributes, attrs...)
+	}
+}
+
+// WithRawStatements records statements as written instead of replacing literals with ?
+func WithRawStatements() Option {
+	return func(c *config) {
+		c.rawStatements = true
+	}
+}
+
+// WithSQLCommenter appends the trace context to executed statements as a
+// sqlcommenter comment, e.g. /*traceparent='00-...'*/, so database logs link
+// back to the trace. Prepared statements are left as is to keep them cacheable.
+func WithSQLCommenter() Option {
+	return func(c *config) {
+		c.commenter = true
+	}
+}
+
+// WithPingSpans creates spans for connection pings, which are skipped by default
+func WithPingSpans() Option {
+	return func(c *config) {
+		c.pingSpans = true
+	}
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{
+		tracerProvider: otel.GetTracerProvider(),
+		propagator:     propagation.TraceContext{},
+		system:         semconv.DBSystemOtherSQL.Value.AsString(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.attributes = append([]attribute.KeyValue{semconv.DBSystemKey.String(c.system)}, c.attributes...)
+	return c
+}
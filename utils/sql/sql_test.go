@@ -0,0 +1,290 @@
+package sql_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	tracing_sql "github.com/weeb-vip/go-tracing-lib/utils/sql"
+)
+
+// fakeDriver is an in-memory driver recording the statements it receives
+type fakeDriver struct {
+	mu      sync.Mutex
+	queries []string
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) {
+	return &fakeConn{driver: d}, nil
+}
+
+func (d *fakeDriver) record(query string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.queries = append(d.queries, query)
+	if strings.Contains(query, "broken") {
+		return errors.New("relation does not exist")
+	}
+	return nil
+}
+
+type fakeConn struct {
+	driver *fakeDriver
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{conn: c, query: query}, nil
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) { return &fakeTx{}, nil }
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	if err := c.driver.record(query); err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(2), nil
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	if err := c.driver.record(query); err != nil {
+		return nil, err
+	}
+	rows := &fakeRows{values: []string{"alice", "bob", "carol"}}
+	if strings.Contains(query, "; SELECT") {
+		rows.next = []string{"dave"}
+	}
+	return rows, nil
+}
+
+// CheckNamedValue accepts string slices, like drivers binding arrays do
+func (c *fakeConn) CheckNamedValue(value *driver.NamedValue) error {
+	if _, ok := value.Value.([]string); ok {
+		return nil
+	}
+	return driver.ErrSkip
+}
+
+type fakeStmt struct {
+	conn  *fakeConn
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	return s.conn.ExecContext(context.Background(), s.query, nil)
+}
+
+func (s *fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	return s.conn.QueryContext(context.Background(), s.query, nil)
+}
+
+type fakeTx struct{}
+
+func (t *fakeTx) Commit() error   { return nil }
+func (t *fakeTx) Rollback() error { return nil }
+
+// fakeRows returns a result set of names followed by an optional second one
+type fakeRows struct {
+	values []string
+	next   []string
+}
+
+func (r *fakeRows) Columns() []string { return []string{"name"} }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) ColumnTypeDatabaseTypeName(int) string { return "TEXT" }
+
+func (r *fakeRows) HasNextResultSet() bool { return r.next != nil }
+
+func (r *fakeRows) NextResultSet() error {
+	if r.next == nil {
+		return io.EOF
+	}
+	r.values, r.next = r.next, nil
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	dest[0], r.values = r.values[0], r.values[1:]
+	return nil
+}
+
+func open(t *testing.T, opts ...tracing_sql.Option) (*sql.DB, *fakeDriver, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	fake := &fakeDriver{}
+	opts = append(opts,
+		tracing_sql.WithTracerProvider(trace.NewTracerProvider(trace.WithSpanProcessor(recorder))),
+		tracing_sql.WithDBSystem("postgresql"),
+	)
+
+	db := sql.OpenDB(tracing_sql.WrapConnector(&connector{driver: fake}, opts...))
+	t.Cleanup(func() { db.Close() })
+	return db, fake, recorder
+}
+
+type connector struct {
+	driver *fakeDriver
+}
+
+func (c *connector) Connect(context.Context) (driver.Conn, error) { return c.driver.Open("") }
+func (c *connector) Driver() driver.Driver                        { return c.driver }
+
+func TestWrapConnector(t *testing.T) {
+	t.Run("Should trace queries with normalised statements and row counts", func(t *testing.T) {
+		a := assert.New(t)
+		db, _, recorder := open(t)
+
+		rows, err := db.QueryContext(context.Background(), "SELECT name\n  FROM users WHERE id = 42 AND role = 'admin'")
+		a.NoError(err)
+		count := 0
+		for rows.Next() {
+			count++
+		}
+		a.NoError(rows.Close())
+		a.Equal(3, count)
+
+		span := recorder.Ended()[0]
+		a.Equal("SELECT", span.Name())
+		a.Contains(span.Attributes(), attribute.String("db.system", "postgresql"))
+		a.Contains(span.Attributes(), attribute.String("db.statement", "SELECT name FROM users WHERE id = ? AND role = ?"))
+		a.Contains(span.Attributes(), attribute.Int64("db.rows_returned", 3))
+	})
+
+	t.Run("Should trace transactions and record errors", func(t *testing.T) {
+		a := assert.New(t)
+		db, _, recorder := open(t)
+
+		tx, err := db.BeginTx(context.Background(), nil)
+		a.NoError(err)
+		result, err := tx.Exec("UPDATE users SET name = $1", "dave")
+		a.NoError(err)
+		affected, _ := result.RowsAffected()
+		a.EqualValues(2, affected)
+		_, err = tx.Exec("DELETE FROM broken")
+		a.Error(err)
+		a.NoError(tx.Commit())
+
+		spans := recorder.Ended()
+		a.Len(spans, 4)
+		a.Equal("sql.begin", spans[0].Name())
+		a.Equal("UPDATE", spans[1].Name())
+		a.Contains(spans[1].Attributes(), attribute.String("db.statement", "UPDATE users SET name = $1"))
+		a.Contains(spans[1].Attributes(), attribute.Int64("db.rows_affected", 2))
+		a.Equal(codes.Error, spans[2].Status().Code)
+		a.Equal("sql.commit", spans[3].Name())
+	})
+
+	t.Run("Should append the trace context as a SQL comment", func(t *testing.T) {
+		a := assert.New(t)
+		db, fake, _ := open(t, tracing_sql.WithSQLCommenter(), tracing_sql.WithPropagator(propagation.TraceContext{}))
+
+		ctx, span := trace.NewTracerProvider().Tracer("test").Start(context.Background(), "test")
+		defer span.End()
+
+		_, err := db.ExecContext(ctx, "DELETE FROM sessions;")
+		a.NoError(err)
+
+		a.Len(fake.queries, 1)
+		a.Regexp(`^DELETE FROM sessions /\*traceparent='00-`+span.SpanContext().TraceID().String()+`-[0-9a-f]{16}-01'\*/;$`, fake.queries[0])
+	})
+
+	t.Run("Should keep baggage out of SQL comments by default", func(t *testing.T) {
+		a := assert.New(t)
+		global := otel.GetTextMapPropagator()
+		defer otel.SetTextMapPropagator(global)
+		otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+		db, fake, _ := open(t, tracing_sql.WithSQLCommenter())
+
+		member, _ := baggage.NewMember("user.id", "42")
+		bag, _ := baggage.New(member)
+		ctx, span := trace.NewTracerProvider().Tracer("test").Start(baggage.ContextWithBaggage(context.Background(), bag), "test")
+		defer span.End()
+
+		_, err := db.ExecContext(ctx, "DELETE FROM sessions")
+		a.NoError(err)
+
+		a.Len(fake.queries, 1)
+		a.Contains(fake.queries[0], "traceparent=")
+		a.NotContains(fake.queries[0], "baggage")
+	})
+
+	t.Run("Should pass column types and result sets through", func(t *testing.T) {
+		a := assert.New(t)
+		db, _, recorder := open(t)
+
+		rows, err := db.QueryContext(context.Background(), "SELECT name FROM users; SELECT name FROM admins")
+		a.NoError(err)
+		types, err := rows.ColumnTypes()
+		a.NoError(err)
+		a.Equal("TEXT", types[0].DatabaseTypeName())
+
+		var names []string
+		for {
+			for rows.Next() {
+				var name string
+				a.NoError(rows.Scan(&name))
+				names = append(names, name)
+			}
+			if !rows.NextResultSet() {
+				break
+			}
+		}
+		a.NoError(rows.Err())
+		a.NoError(rows.Close())
+
+		a.Equal([]string{"alice", "bob", "carol", "dave"}, names)
+		a.Contains(recorder.Ended()[0].Attributes(), attribute.Int64("db.rows_returned", 4))
+	})
+
+	t.Run("Should check the arguments of prepared statements with the connection", func(t *testing.T) {
+		a := assert.New(t)
+		db, _, recorder := open(t)
+
+		stmt, err := db.Prepare("SELECT name FROM users WHERE role = ANY($1)")
+		a.NoError(err)
+		defer stmt.Close()
+		rows, err := stmt.Query([]string{"admin", "owner"})
+		a.NoError(err)
+		a.NoError(rows.Close())
+
+		_, err = stmt.Query(struct{}{})
+		a.ErrorContains(err, "unsupported type")
+		a.Equal("SELECT", recorder.Ended()[len(recorder.Ended())-1].Name())
+	})
+
+	t.Run("Should refuse transaction options the driver cannot honour", func(t *testing.T) {
+		a := assert.New(t)
+		db, _, recorder := open(t)
+
+		_, err := db.BeginTx(context.Background(), &sql.TxOptions{Isolation: sql.LevelSerializable})
+		a.EqualError(err, "sql: driver does not support non-default isolation level")
+		_, err = db.BeginTx(context.Background(), &sql.TxOptions{ReadOnly: true})
+		a.EqualError(err, "sql: driver does not support read-only transactions")
+
+		spans := recorder.Ended()
+		a.Len(spans, 2)
+		a.Equal(codes.Error, spans[0].Status().Code)
+	})
+}
@@ -0,0 +1,58 @@
+package sql
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/otel/propagation"
+)
+
+var (
+	stringLiteral  = regexp.MustCompile(`'(?:[^']|'')*'`)
+	numericLiteral = regexp.MustCompile(`(^|[^\w$.])\d+(?:\.\d+)?\b`)
+	whitespace     = regexp.MustCompile(`\s+`)
+)
+
+// normalizeStatement collapses whitespace and replaces string and numeric
+// literals with ? so statements do not leak values and group well
+func normalizeStatement(query string) string {
+	query = stringLiteral.ReplaceAllString(query, "?")
+	query = numericLiteral.ReplaceAllString(query, "${1}?")
+	return strings.TrimSpace(whitespace.ReplaceAllString(query, " "))
+}
+
+// operation returns the first keyword of query in upper case, e.g. SELECT
+func operation(query string) string {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToUpper(strings.TrimLeft(fields[0], "("))
+}
+
+// commentStatement appends the trace context of ctx to query following the
+// sqlcommenter format. Queries that already carry a comment are left alone.
+func commentStatement(ctx context.Context, propagator propagation.TextMapPropagator, query string) string {
+	if strings.Contains(query, "/*") {
+		return query
+	}
+
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return query
+	}
+
+	keys := carrier.Keys()
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, url.QueryEscape(key)+"='"+url.PathEscape(carrier.Get(key))+"'")
+	}
+
+	trimmed := strings.TrimRight(query, " \t\n;")
+	return trimmed + " /*" + strings.Join(pairs, ",") + "*/" + query[len(trimmed):]
+}
@@ -0,0 +1,158 @@
+package sql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+type tracedStmt struct {
+	stmt   driver.Stmt
+	conn   *tracedConn
+	query  string
+	config *config
+}
+
+var _ interface {
+	driver.Stmt
+	driver.StmtExecContext
+	driver.StmtQueryContext
+	driver.NamedValueChecker
+} = &tracedStmt{}
+
+func (s *tracedStmt) Close() error {
+	return s.stmt.Close()
+}
+
+func (s *tracedStmt) NumInput() int {
+	return s.stmt.NumInput()
+}
+
+func (s *tracedStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), namedValues(args))
+}
+
+func (s *tracedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	ctx, span := s.config.startSpan(ctx, methodExec, s.query)
+	defer span.End()
+
+	var result driver.Result
+	var err error
+	if execer, ok := s.stmt.(driver.StmtExecContext); ok {
+		result, err = execer.ExecContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = plainValues(args); err == nil {
+			result, err = s.stmt.Exec(values)
+		}
+	}
+	if err != nil {
+		recordError(span, err)
+		return nil, err
+	}
+	recordRowsAffected(span, result)
+	return result, nil
+}
+
+func (s *tracedStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), namedValues(args))
+}
+
+func (s *tracedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	ctx, span := s.config.startSpan(ctx, methodQuery, s.query)
+
+	var rows driver.Rows
+	var err error
+	if queryer, ok := s.stmt.(driver.StmtQueryContext); ok {
+		rows, err = queryer.QueryContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = plainValues(args); err == nil {
+			rows, err = s.stmt.Query(values)
+		}
+	}
+	if err != nil {
+		finishSpan(span, err)
+		return nil, err
+	}
+	return &tracedRows{rows: rows, span: span}, nil
+}
+
+// CheckNamedValue checks value as database/sql would with the driver's
+// statement: database/sql only asks the connection when the statement has no
+// checker, and tracedStmt always has one. The checker of the statement comes
+// first, then the one of the connection, then the ColumnConverter of the
+// statement, and driver.ErrSkip leaves the default conversion to database/sql.
+func (s *tracedStmt) CheckNamedValue(value *driver.NamedValue) error {
+	if checker, ok := s.stmt.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(value)
+	}
+	if err := s.conn.CheckNamedValue(value); err != driver.ErrSkip {
+		return err
+	}
+	if converter, ok := s.stmt.(driver.ColumnConverter); ok {
+		return convertColumn(converter, s.stmt.NumInput(), value)
+	}
+	return driver.ErrSkip
+}
+
+// convertColumn converts value with the column converter of its parameter,
+// as database/sql does for statements implementing driver.ColumnConverter
+func convertColumn(converter driver.ColumnConverter, want int, value *driver.NamedValue) error {
+	index := value.Ordinal - 1
+	if want >= 0 && want <= index {
+		return nil
+	}
+
+	if valuer, ok := value.Value.(driver.Valuer); ok {
+		v, err := callValuer(valuer)
+		if err != nil {
+			return err
+		}
+		if !driver.IsValue(v) {
+			return fmt.Errorf("non-subset type %T returned from Value", v)
+		}
+		value.Value = v
+	}
+
+	arg := value.Value
+	converted, err := converter.ColumnConverter(index).ConvertValue(arg)
+	if err != nil {
+		return err
+	}
+	if !driver.IsValue(converted) {
+		return fmt.Errorf("driver ColumnConverter error converted %T to unsupported type %T", arg, converted)
+	}
+	value.Value = converted
+	return nil
+}
+
+// callValuer returns nil for nil pointers whose element type implements driver.Valuer
+func callValuer(valuer driver.Valuer) (driver.Value, error) {
+	if v := reflect.ValueOf(valuer); v.Kind() == reflect.Pointer && v.IsNil() &&
+		v.Type().Elem().Implements(reflect.TypeOf((*driver.Valuer)(nil)).Elem()) {
+		return nil, nil
+	}
+	return valuer.Value()
+}
+
+func namedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+	}
+	return named
+}
+
+func plainValues(args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, errors.New("sql: driver does not support the use of Named Parameters")
+		}
+		values[i] = arg.Value
+	}
+	return values, nil
+}
@@ -0,0 +1,115 @@
+package sql
+
+import (
+	"context"
+	"database/sql/driver"
+	"io"
+	"reflect"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type tracedTx struct {
+	tx     driver.Tx
+	ctx    context.Context
+	config *config
+}
+
+// Commit is traced in the context the transaction was begun with, the
+// driver interface does not pass the caller's context
+func (t *tracedTx) Commit() error {
+	_, span := t.config.startSpan(t.ctx, methodCommit, "")
+	err := t.tx.Commit()
+	finishSpan(span, err)
+	return err
+}
+
+// Rollback is traced like Commit
+func (t *tracedTx) Rollback() error {
+	_, span := t.config.startSpan(t.ctx, methodRollback, "")
+	err := t.tx.Rollback()
+	finishSpan(span, err)
+	return err
+}
+
+// tracedRows ends the query span once the rows are closed, recording how many
+// were read. The optional driver.Rows interfaces fall back to what database/sql
+// does when the driver's rows do not implement them.
+type tracedRows struct {
+	rows  driver.Rows
+	span  trace.Span
+	count int64
+}
+
+func (r *tracedRows) Columns() []string {
+	return r.rows.Columns()
+}
+
+func (r *tracedRows) Close() error {
+	err := r.rows.Close()
+	r.span.SetAttributes(RowsReturnedKey.Int64(r.count))
+	finishSpan(r.span, err)
+	return err
+}
+
+func (r *tracedRows) Next(dest []driver.Value) error {
+	err := r.rows.Next(dest)
+	if err == nil {
+		r.count++
+	} else if err != io.EOF {
+		recordError(r.span, err)
+	}
+	return err
+}
+
+func (r *tracedRows) HasNextResultSet() bool {
+	rows, ok := r.rows.(driver.RowsNextResultSet)
+	return ok && rows.HasNextResultSet()
+}
+
+func (r *tracedRows) NextResultSet() error {
+	rows, ok := r.rows.(driver.RowsNextResultSet)
+	if !ok {
+		return io.EOF
+	}
+	err := rows.NextResultSet()
+	if err != nil && err != io.EOF {
+		recordError(r.span, err)
+	}
+	return err
+}
+
+func (r *tracedRows) ColumnTypeScanType(index int) reflect.Type {
+	if rows, ok := r.rows.(driver.RowsColumnTypeScanType); ok {
+		return rows.ColumnTypeScanType(index)
+	}
+	return reflect.TypeOf(new(any)).Elem()
+}
+
+func (r *tracedRows) ColumnTypeDatabaseTypeName(index int) string {
+	if rows, ok := r.rows.(driver.RowsColumnTypeDatabaseTypeName); ok {
+		return rows.ColumnTypeDatabaseTypeName(index)
+	}
+	return ""
+}
+
+func (r *tracedRows) ColumnTypeLength(index int) (int64, bool) {
+	if rows, ok := r.rows.(driver.RowsColumnTypeLength); ok {
+		return rows.ColumnTypeLength(index)
+	}
+	return 0, false
+}
+
+func (r *tracedRows) ColumnTypeNullable(index int) (bool, bool) {
+	if rows, ok := r.rows.(driver.RowsColumnTypeNullable); ok {
+		return rows.ColumnTypeNullable(index)
+	}
+	return false, false
+}
+
+func (r *tracedRows) ColumnTypePrecisionScale(index int) (int64, int64, bool) {
+	if rows, ok := r.rows.(driver.RowsColumnTypePrecisionScale); ok {
+		return rows.ColumnTypePrecisionScale(index)
+	}
+	return 0, 0, false
+}
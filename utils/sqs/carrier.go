@@ -0,0 +1,111 @@
+package sqs
+
+import (
+	"encoding/json"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	snstypes "github.com/aws/aws-sdk-go-v2/service/sns/types"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// MaxMessageAttributes is the number of message attributes SQS and SNS accept per message
+const MaxMessageAttributes = 10
+
+var (
+	_ propagation.TextMapCarrier = MessageAttributeCarrier{}
+	_ propagation.TextMapCarrier = SNSMessageAttributeCarrier{}
+)
+
+// MessageAttributeCarrier adapts SQS message attributes to a propagation.TextMapCarrier.
+// Set drops new keys once the message has MaxMessageAttributes attributes.
+type MessageAttributeCarrier map[string]types.MessageAttributeValue
+
+func (c MessageAttributeCarrier) Get(key string) string {
+	if value, ok := c[key]; ok && value.StringValue != nil {
+		return *value.StringValue
+	}
+	return ""
+}
+
+func (c MessageAttributeCarrier) Set(key string, value string) {
+	if _, ok := c[key]; !ok && len(c) >= MaxMessageAttributes {
+		return
+	}
+	c[key] = types.MessageAttributeValue{DataType: aws.String("String"), StringValue: aws.String(value)}
+}
+
+func (c MessageAttributeCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// SNSMessageAttributeCarrier adapts SNS message attributes to a propagation.TextMapCarrier.
+// Set drops new keys once the message has MaxMessageAttributes attributes.
+type SNSMessageAttributeCarrier map[string]snstypes.MessageAttributeValue
+
+func (c SNSMessageAttributeCarrier) Get(key string) string {
+	if value, ok := c[key]; ok && value.StringValue != nil {
+		return *value.StringValue
+	}
+	return ""
+}
+
+func (c SNSMessageAttributeCarrier) Set(key string, value string) {
+	if _, ok := c[key]; !ok && len(c) >= MaxMessageAttributes {
+		return
+	}
+	c[key] = snstypes.MessageAttributeValue{DataType: aws.String("String"), StringValue: aws.String(value)}
+}
+
+func (c SNSMessageAttributeCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// SNSEnvelope is the body of an SQS message delivered from an SNS topic without raw message delivery
+type SNSEnvelope struct {
+	Type              string                          `json:"Type"`
+	MessageID         string                          `json:"MessageId"`
+	TopicArn          string                          `json:"TopicArn"`
+	Message           string                          `json:"Message"`
+	MessageAttributes map[string]SNSEnvelopeAttribute `json:"MessageAttributes"`
+}
+
+// SNSEnvelopeAttribute is a message attribute of an SNSEnvelope
+type SNSEnvelopeAttribute struct {
+	Type  string `json:"Type"`
+	Value string `json:"Value"`
+}
+
+// ParseSNSEnvelope parses body as an SNS notification, reporting whether it is one
+func ParseSNSEnvelope(body string) (*SNSEnvelope, bool) {
+	var envelope SNSEnvelope
+	if err := json.Unmarshal([]byte(body), &envelope); err != nil || envelope.Type != "Notification" || envelope.TopicArn == "" {
+		return nil, false
+	}
+	return &envelope, true
+}
+
+// NewMessageCarrier returns a carrier over the attributes of a received SQS
+// message, or over the attributes of its SNS envelope when the message has none
+func NewMessageCarrier(msg types.Message) propagation.TextMapCarrier {
+	if len(msg.MessageAttributes) > 0 || msg.Body == nil {
+		return MessageAttributeCarrier(msg.MessageAttributes)
+	}
+	envelope, ok := ParseSNSEnvelope(*msg.Body)
+	if !ok {
+		return MessageAttributeCarrier(msg.MessageAttributes)
+	}
+	carrier := propagation.MapCarrier{}
+	for k, v := range envelope.MessageAttributes {
+		carrier[k] = v.Value
+	}
+	return carrier
+}
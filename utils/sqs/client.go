@@ -0,0 +1,143 @@
+package sqs
+
+import (
+	"context"
+	"path"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/weeb-vip/go-tracing-lib/logger"
+)
+
+// API is the part of *sqs.Client used by Client
+type API interface {
+	SendMessage(ctx context.Context, params *sqs.SendMessageInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error)
+	ReceiveMessage(ctx context.Context, params *sqs.ReceiveMessageInput, optFns ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error)
+}
+
+// MessageHandler processes a received message within its consumer span
+type MessageHandler func(ctx context.Context, msg types.Message) error
+
+// Client sends and receives SQS messages in messaging spans, carrying the
+// trace context in the message attributes
+type Client struct {
+	api    API
+	config *config
+}
+
+// NewClient creates a traced client over api, usually an *sqs.Client
+func NewClient(api API, opts ...Option) *Client {
+	return &Client{api: api, config: newConfig(opts)}
+}
+
+// SendMessage sends a message like sqs.Client.SendMessage in a producer span
+func (c *Client) SendMessage(ctx context.Context, params *sqs.SendMessageInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error) {
+	queue := queueName(params.QueueUrl)
+	ctx, span := c.config.tracer().Start(ctx, queue+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystem("aws_sqs"),
+			semconv.MessagingOperationPublish,
+			semconv.MessagingDestinationName(queue),
+			semconv.MessagingMessagePayloadSizeBytes(len(aws.ToString(params.MessageBody))),
+		),
+	)
+	defer span.End()
+
+	input := *params
+	attributes := make(MessageAttributeCarrier, len(params.MessageAttributes)+2)
+	for k, v := range params.MessageAttributes {
+		attributes[k] = v
+	}
+	c.config.propagator.Inject(ctx, attributes)
+	input.MessageAttributes = attributes
+
+	output, err := c.api.SendMessage(ctx, &input, optFns...)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	span.SetAttributes(semconv.MessagingMessageID(aws.ToString(output.MessageId)))
+	return output, nil
+}
+
+// ReceiveMessage receives messages like sqs.Client.ReceiveMessage in a client
+// span, requesting the message attributes used by the propagator
+func (c *Client) ReceiveMessage(ctx context.Context, params *sqs.ReceiveMessageInput, optFns ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error) {
+	queue := queueName(params.QueueUrl)
+	ctx, span := c.config.tracer().Start(ctx, queue+" receive",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.MessagingSystem("aws_sqs"),
+			semconv.MessagingOperationReceive,
+			semconv.MessagingSourceName(queue),
+		),
+	)
+	defer span.End()
+
+	input := *params
+	if !slices.Contains(input.MessageAttributeNames, "All") && !slices.Contains(input.MessageAttributeNames, ".*") {
+		input.MessageAttributeNames = append(slices.Clone(input.MessageAttributeNames), c.config.propagator.Fields()...)
+	}
+
+	output, err := c.api.ReceiveMessage(ctx, &input, optFns...)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	span.SetAttributes(semconv.MessagingBatchMessageCount(len(output.Messages)))
+	return output, nil
+}
+
+// Handle runs handler for a message received from queueURL in a consumer span
+// parented to, or linked with, the producer's span, which is read from the
+// message attributes or from the SNS envelope. The context carries the trace-aware logger.
+func (c *Client) Handle(ctx context.Context, queueURL string, msg types.Message, handler MessageHandler) error {
+	queue := queueName(&queueURL)
+	attrs := []attribute.KeyValue{
+		semconv.MessagingSystem("aws_sqs"),
+		semconv.MessagingOperationProcess,
+		semconv.MessagingSourceName(queue),
+		semconv.MessagingMessageID(aws.ToString(msg.MessageId)),
+		semconv.MessagingMessagePayloadSizeBytes(len(aws.ToString(msg.Body))),
+	}
+	startOpts := []trace.SpanStartOption{trace.WithSpanKind(trace.SpanKindConsumer), trace.WithAttributes(attrs...)}
+
+	producerCtx := c.config.propagator.Extract(ctx, NewMessageCarrier(msg))
+	if c.config.linkToProducer {
+		if producer := trace.SpanContextFromContext(producerCtx); producer.IsValid() {
+			startOpts = append(startOpts, trace.WithLinks(trace.Link{SpanContext: producer}))
+		}
+	} else {
+		ctx = producerCtx
+	}
+
+	ctx, span := c.config.tracer().Start(ctx, queue+" process", startOpts...)
+	defer span.End()
+
+	ctx = logger.ContextWithTraceFormats(ctx, c.config.logFormats...)
+
+	if err := handler(ctx, msg); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	return nil
+}
+
+// queueName returns the last segment of a queue URL
+func queueName(queueURL *string) string {
+	if queueURL == nil || *queueURL == "" {
+		return "(unknown)"
+	}
+	return path.Base(*queueURL)
+}
@@ -0,0 +1,70 @@
+package sqs
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SNSAPI is the part of *sns.Client used by SNSPublisher
+type SNSAPI interface {
+	Publish(ctx context.Context, params *sns.PublishInput, optFns ...func(*sns.Options)) (*sns.PublishOutput, error)
+}
+
+// SNSPublisher publishes SNS messages in a producer span, carrying the trace
+// context in the message attributes. Subscribed SQS queues receive them in
+// the message attributes with raw message delivery, or in the SNS envelope without.
+type SNSPublisher struct {
+	api    SNSAPI
+	config *config
+}
+
+// NewSNSPublisher creates a traced publisher over api, usually an *sns.Client
+func NewSNSPublisher(api SNSAPI, opts ...Option) *SNSPublisher {
+	return &SNSPublisher{api: api, config: newConfig(opts)}
+}
+
+// Publish publishes a message like sns.Client.Publish
+func (p *SNSPublisher) Publish(ctx context.Context, params *sns.PublishInput, optFns ...func(*sns.Options)) (*sns.PublishOutput, error) {
+	topic := topicName(aws.ToString(params.TopicArn))
+	ctx, span := p.config.tracer().Start(ctx, topic+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystem("aws_sns"),
+			semconv.MessagingOperationPublish,
+			semconv.MessagingDestinationName(topic),
+			semconv.MessagingMessagePayloadSizeBytes(len(aws.ToString(params.Message))),
+		),
+	)
+	defer span.End()
+
+	input := *params
+	attributes := make(SNSMessageAttributeCarrier, len(params.MessageAttributes)+2)
+	for k, v := range params.MessageAttributes {
+		attributes[k] = v
+	}
+	p.config.propagator.Inject(ctx, attributes)
+	input.MessageAttributes = attributes
+
+	output, err := p.api.Publish(ctx, &input, optFns...)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	span.SetAttributes(semconv.MessagingMessageID(aws.ToString(output.MessageId)))
+	return output, nil
+}
+
+// topicName returns the name of a topic ARN, arn:aws:sns:region:account:name
+func topicName(arn string) string {
+	if arn == "" {
+		return "(unknown)"
+	}
+	return arn[strings.LastIndex(arn, ":")+1:]
+}
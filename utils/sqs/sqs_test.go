@@ -0,0 +1,196 @@
+package sqs_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+
+	tracing_sqs "github.com/weeb-vip/go-tracing-lib/utils/sqs"
+)
+
+type attributeValue struct {
+	DataType    string
+	StringValue string
+}
+
+type message struct {
+	MessageId         string
+	Body              string
+	MessageAttributes map[string]attributeValue `json:",omitempty"`
+}
+
+// fakeAWS stands in for SQS, speaking JSON, and SNS, speaking the query
+// protocol, delivering published notifications to its single queue
+type fakeAWS struct {
+	mu    sync.Mutex
+	queue []message
+	next  int
+}
+
+func (f *fakeAWS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.next++
+	id := fmt.Sprintf("msg-%d", f.next)
+
+	switch r.Header.Get("X-Amz-Target") {
+	case "AmazonSQS.SendMessage":
+		var input message
+		_ = json.NewDecoder(r.Body).Decode(&input)
+		input.MessageId = id
+		f.queue = append(f.queue, input)
+		_ = json.NewEncoder(w).Encode(map[string]string{"MessageId": id})
+	case "AmazonSQS.ReceiveMessage":
+		var input struct{ MessageAttributeNames []string }
+		_ = json.NewDecoder(r.Body).Decode(&input)
+		for i, msg := range f.queue {
+			for name := range msg.MessageAttributes {
+				if !slices.Contains(input.MessageAttributeNames, name) {
+					delete(f.queue[i].MessageAttributes, name)
+				}
+			}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"Messages": f.queue})
+		f.queue = nil
+	default:
+		_ = r.ParseForm()
+		attributes := map[string]tracing_sqs.SNSEnvelopeAttribute{}
+		for i := 1; r.Form.Get(fmt.Sprintf("MessageAttributes.entry.%d.Name", i)) != ""; i++ {
+			attributes[r.Form.Get(fmt.Sprintf("MessageAttributes.entry.%d.Name", i))] = tracing_sqs.SNSEnvelopeAttribute{
+				Type:  "String",
+				Value: r.Form.Get(fmt.Sprintf("MessageAttributes.entry.%d.Value.StringValue", i)),
+			}
+		}
+		body, _ := json.Marshal(tracing_sqs.SNSEnvelope{
+			Type:              "Notification",
+			MessageID:         id,
+			TopicArn:          r.Form.Get("TopicArn"),
+			Message:           r.Form.Get("Message"),
+			MessageAttributes: attributes,
+		})
+		f.queue = append(f.queue, message{MessageId: id, Body: string(body)})
+		w.Header().Set("Content-Type", "text/xml")
+		fmt.Fprintf(w, "<PublishResponse><PublishResult><MessageId>%s</MessageId></PublishResult></PublishResponse>", id)
+	}
+}
+
+const queueURL = "https://sqs.us-east-1.amazonaws.com/123456789012/users"
+
+func setup(t *testing.T) (*sqs.Client, *sns.Client, []tracing_sqs.Option, *tracetest.SpanRecorder) {
+	server := httptest.NewServer(&fakeAWS{})
+	t.Cleanup(server.Close)
+
+	sqsClient := sqs.New(sqs.Options{
+		Region:                           "us-east-1",
+		BaseEndpoint:                     aws.String(server.URL),
+		Credentials:                      aws.AnonymousCredentials{},
+		DisableMessageChecksumValidation: true,
+	})
+	snsClient := sns.New(sns.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.URL),
+		Credentials:  aws.AnonymousCredentials{},
+	})
+
+	recorder := tracetest.NewSpanRecorder()
+	return sqsClient, snsClient, []tracing_sqs.Option{
+		tracing_sqs.WithTracerProvider(trace.NewTracerProvider(trace.WithSpanProcessor(recorder))),
+		tracing_sqs.WithPropagator(propagation.TraceContext{}),
+	}, recorder
+}
+
+func spanNamed(recorder *tracetest.SpanRecorder, name string) trace.ReadOnlySpan {
+	for _, span := range recorder.Ended() {
+		if span.Name() == name {
+			return span
+		}
+	}
+	return nil
+}
+
+func receiveOne(t *testing.T, client *tracing_sqs.Client) types.Message {
+	output, err := client.ReceiveMessage(context.Background(), &sqs.ReceiveMessageInput{QueueUrl: aws.String(queueURL)})
+	assert.NoError(t, err)
+	assert.Len(t, output.Messages, 1)
+	return output.Messages[0]
+}
+
+func TestCarrier(t *testing.T) {
+	t.Run("Should respect the message attribute limit", func(t *testing.T) {
+		a := assert.New(t)
+		carrier := tracing_sqs.MessageAttributeCarrier{}
+		for i := 0; i < tracing_sqs.MaxMessageAttributes; i++ {
+			carrier.Set(fmt.Sprintf("attribute-%d", i), "value")
+		}
+
+		carrier.Set("traceparent", "dropped")
+		carrier.Set("attribute-0", "replaced")
+
+		a.Len(carrier, tracing_sqs.MaxMessageAttributes)
+		a.Empty(carrier.Get("traceparent"))
+		a.Equal("replaced", carrier.Get("attribute-0"))
+	})
+}
+
+func TestClient(t *testing.T) {
+	t.Run("Should continue the sender's trace when handling messages", func(t *testing.T) {
+		a := assert.New(t)
+		sqsClient, _, opts, recorder := setup(t)
+		client := tracing_sqs.NewClient(sqsClient, opts...)
+
+		_, err := client.SendMessage(context.Background(), &sqs.SendMessageInput{QueueUrl: aws.String(queueURL), MessageBody: aws.String("created")})
+		a.NoError(err)
+		msg := receiveOne(t, client)
+		a.NoError(client.Handle(context.Background(), queueURL, msg, func(ctx context.Context, msg types.Message) error {
+			return nil
+		}))
+
+		producer := spanNamed(recorder, "users publish")
+		a.Equal(oteltrace.SpanKindProducer, producer.SpanKind())
+		a.Contains(producer.Attributes(), attribute.String("messaging.system", "aws_sqs"))
+		a.Contains(producer.Attributes(), attribute.String("messaging.message.id", "msg-1"))
+		a.Contains(spanNamed(recorder, "users receive").Attributes(), attribute.Int("messaging.batch.message_count", 1))
+
+		consumer := spanNamed(recorder, "users process")
+		a.Equal(oteltrace.SpanKindConsumer, consumer.SpanKind())
+		a.Equal(producer.SpanContext().SpanID(), consumer.Parent().SpanID())
+	})
+
+	t.Run("Should read the trace context from SNS envelopes", func(t *testing.T) {
+		a := assert.New(t)
+		sqsClient, snsClient, opts, recorder := setup(t)
+		client := tracing_sqs.NewClient(sqsClient, append(opts, tracing_sqs.WithLinkToProducer())...)
+
+		_, err := tracing_sqs.NewSNSPublisher(snsClient, opts...).Publish(context.Background(), &sns.PublishInput{
+			TopicArn: aws.String("arn:aws:sns:us-east-1:123456789012:events"),
+			Message:  aws.String("created"),
+		})
+		a.NoError(err)
+		msg := receiveOne(t, client)
+		a.NoError(client.Handle(context.Background(), queueURL, msg, func(ctx context.Context, msg types.Message) error {
+			return nil
+		}))
+
+		producer := spanNamed(recorder, "events publish")
+		a.Contains(producer.Attributes(), attribute.String("messaging.system", "aws_sns"))
+		consumer := spanNamed(recorder, "users process")
+		a.False(consumer.Parent().IsValid())
+		a.Equal(producer.SpanContext().SpanID(), consumer.Links()[0].SpanContext.SpanID())
+	})
+}
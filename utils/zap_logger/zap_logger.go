@@ -0,0 +1,123 @@
+package zap_logger
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/weeb-vip/go-tracing-lib/internal/logfields"
+	"github.com/weeb-vip/go-tracing-lib/logger"
+	"github.com/weeb-vip/go-tracing-lib/providers"
+)
+
+type ctxKey struct{}
+
+// contextFieldKey marks the field produced by Context. The field uses
+// zapcore.SkipType so encoders ignore it if the core is not wrapped.
+const contextFieldKey = "go-tracing-lib.context"
+
+// Option configures the core
+type Option func(*traceCore)
+
+// WithFormats selects the trace identifier formats, defaults to logger.DefaultTraceFormats
+func WithFormats(formats ...providers.LogFormat) Option {
+	return func(c *traceCore) {
+		c.formats = formats
+	}
+}
+
+type traceCore struct {
+	zapcore.Core
+	formats []providers.LogFormat
+	ctx     context.Context
+}
+
+// NewCore wraps core so that entries logged with a Context field, or from a
+// logger created with one, carry the trace and span IDs of that context.
+func NewCore(core zapcore.Core, opts ...Option) zapcore.Core {
+	c := &traceCore{
+		Core:    core,
+		formats: logger.DefaultTraceFormats,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Wrap returns a copy of l whose core adds trace identifiers
+func Wrap(l *zap.Logger, opts ...Option) *zap.Logger {
+	return l.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return NewCore(core, opts...)
+	}))
+}
+
+// Context returns a field carrying ctx, the trace identifiers are read from it when the entry is written
+func Context(ctx context.Context) zap.Field {
+	return zap.Field{Key: contextFieldKey, Type: zapcore.SkipType, Interface: ctx}
+}
+
+func (c *traceCore) With(fields []zapcore.Field) zapcore.Core {
+	ctx, fields := splitContext(c.ctx, fields)
+	return &traceCore{
+		Core:    c.Core.With(fields),
+		formats: c.formats,
+		ctx:     ctx,
+	}
+}
+
+func (c *traceCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *traceCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	ctx, fields := splitContext(c.ctx, fields)
+	for _, field := range logfields.FromContext(ctx, c.formats) {
+		fields = append(fields, zap.String(field.Key, field.Value))
+	}
+	return c.Core.Write(entry, fields)
+}
+
+// splitContext removes Context fields, returning the last context found or ctx if there is none
+func splitContext(ctx context.Context, fields []zapcore.Field) (context.Context, []zapcore.Field) {
+	out := fields[:0:0]
+	for _, field := range fields {
+		if field.Key == contextFieldKey && field.Type == zapcore.SkipType {
+			if fieldCtx, ok := field.Interface.(context.Context); ok {
+				ctx = fieldCtx
+			}
+			continue
+		}
+		out = append(out, field)
+	}
+	return ctx, out
+}
+
+// FromCtx returns the Logger associated with the ctx bound to the span in ctx.
+// If no logger is associated, the global zap logger is used.
+func FromCtx(ctx context.Context) *zap.Logger {
+	l, ok := ctx.Value(ctxKey{}).(*zap.Logger)
+	if !ok {
+		l = zap.L()
+	}
+	return l.With(Context(ctx))
+}
+
+// FromContext is an alias for FromCtx
+func FromContext(ctx context.Context) *zap.Logger {
+	return FromCtx(ctx)
+}
+
+// WithCtx returns a copy of ctx with the Logger attached.
+func WithCtx(ctx context.Context, l *zap.Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, l)
+}
+
+// WithContext returns a copy of ctx with the Logger attached.
+func WithContext(l *zap.Logger, ctx context.Context) context.Context {
+	return WithCtx(ctx, l)
+}
@@ -0,0 +1,59 @@
+package zap_logger_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/weeb-vip/go-tracing-lib/providers"
+	"github.com/weeb-vip/go-tracing-lib/utils/zap_logger"
+)
+
+func TestNewCore(t *testing.T) {
+	t.Run("Should add trace identifiers from the context field", func(t *testing.T) {
+		a := assert.New(t)
+		core, logs := observer.New(zap.InfoLevel)
+		l := zap.New(zap_logger.NewCore(core))
+
+		ctx, span := trace.NewTracerProvider().Tracer("test").Start(context.Background(), "test")
+		defer span.End()
+
+		l.Info("hello", zap_logger.Context(ctx))
+
+		fields := logs.All()[0].ContextMap()
+		a.Equal(span.SpanContext().TraceID().String(), fields["traceID"])
+		a.Equal(span.SpanContext().SpanID().String(), fields["spanID"])
+		a.Equal(span.SpanContext().TraceID().String(), fields["dd.trace_id"])
+		a.NotContains(fields, "go-tracing-lib.context")
+	})
+
+	t.Run("Should use the logger attached to the context", func(t *testing.T) {
+		a := assert.New(t)
+		core, logs := observer.New(zap.InfoLevel)
+		l := zap.New(zap_logger.NewCore(core, zap_logger.WithFormats(providers.LogFormatDatadog)))
+
+		ctx, span := trace.NewTracerProvider().Tracer("test").Start(context.Background(), "test")
+		defer span.End()
+		ctx = zap_logger.WithContext(l, ctx)
+
+		zap_logger.FromContext(ctx).Info("hello")
+
+		fields := logs.All()[0].ContextMap()
+		a.Equal(span.SpanContext().SpanID().String(), fields["dd.span_id"])
+		a.NotContains(fields, "spanID")
+	})
+
+	t.Run("Should not add fields without a span", func(t *testing.T) {
+		a := assert.New(t)
+		core, logs := observer.New(zap.InfoLevel)
+		l := zap.New(zap_logger.NewCore(core))
+
+		l.Info("hello", zap_logger.Context(context.Background()))
+
+		a.Empty(logs.All()[0].ContextMap())
+	})
+}